@@ -39,16 +39,18 @@ func init() {
 	rootCmd.PersistentFlags().Int("port", 4444, "Port to run the server on")
 
 	// Browser flags
-	rootCmd.PersistentFlags().Int("pool-size", 3, "Number of browser instances in the pool")
+	rootCmd.PersistentFlags().Int("pool-size", 3, "Number of concurrent browser tabs")
 	rootCmd.PersistentFlags().String("chrome-path", "", "Path to Chrome/Chromium executable")
 	rootCmd.PersistentFlags().Int("browser-timeout", 30, "Browser request timeout in seconds")
 	rootCmd.PersistentFlags().Int("max-retries", 3, "Maximum number of retries for browser operations")
 
 	// AI flags
 	rootCmd.PersistentFlags().Bool("ai-enabled", true, "Enable/disable AI features")
+	rootCmd.PersistentFlags().String("ai-provider", "openai", "AI provider type (openai, anthropic, azure, ollama)")
 	rootCmd.PersistentFlags().String("ai-endpoint", "", "AI API endpoint")
 	rootCmd.PersistentFlags().String("ai-key", "", "AI API key")
 	rootCmd.PersistentFlags().String("ai-model", "vltr-mistral-small", "AI model to use")
+	rootCmd.PersistentFlags().String("ai-embedding-model", "text-embedding-3-small", "AI model to use for embeddings")
 
 	// Bind flags to viper
 	bindFlags()
@@ -72,9 +74,11 @@ func bindFlags() {
 		"browser.timeout":     "browser-timeout",
 		"browser.max_retries": "max-retries",
 		"ai.enabled":          "ai-enabled",
+		"ai.provider":         "ai-provider",
 		"ai.api_endpoint":     "ai-endpoint",
 		"ai.api_key":          "ai-key",
 		"ai.model":            "ai-model",
+		"ai.embedding_model":  "ai-embedding-model",
 	}
 
 	for configKey, flagName := range flags {
@@ -89,15 +93,49 @@ func bindFlags() {
 // bindEnvs binds environment variables to viper configuration
 func bindEnvs() {
 	envs := map[string]string{
-		"server.port":         "READER_PORT",
-		"browser.pool_size":   "READER_POOL_SIZE",
-		"browser.chrome_path": "READER_CHROME_PATH",
-		"browser.timeout":     "READER_BROWSER_TIMEOUT",
-		"browser.max_retries": "READER_MAX_RETRIES",
-		"ai.enabled":          "READER_AI_ENABLED",
-		"ai.api_endpoint":     "READER_AI_ENDPOINT",
-		"ai.api_key":          "READER_AI_KEY",
-		"ai.model":            "READER_AI_MODEL",
+		"server.port":                     "READER_PORT",
+		"browser.pool_size":               "READER_POOL_SIZE",
+		"browser.chrome_path":             "READER_CHROME_PATH",
+		"browser.timeout":                 "READER_BROWSER_TIMEOUT",
+		"browser.max_retries":             "READER_MAX_RETRIES",
+		"browser.processes":               "READER_BROWSER_PROCESSES",
+		"browser.max_uses":                "READER_BROWSER_MAX_USES",
+		"browser.max_age":                 "READER_BROWSER_MAX_AGE",
+		"browser.health_check_interval":   "READER_BROWSER_HEALTH_CHECK_INTERVAL",
+		"browser.memory_limit":            "READER_BROWSER_MEMORY_LIMIT",
+		"browser.max_queue":               "READER_BROWSER_MAX_QUEUE",
+		"browser.queue_timeout":           "READER_BROWSER_QUEUE_TIMEOUT",
+		"browser.scroll.max_scrolls":      "READER_BROWSER_SCROLL_MAX_SCROLLS",
+		"browser.scroll.timeout":          "READER_BROWSER_SCROLL_TIMEOUT",
+		"browser.blocking.enabled":        "READER_BROWSER_BLOCKING_ENABLED",
+		"browser.blocking.resource_types": "READER_BROWSER_BLOCKING_RESOURCE_TYPES",
+		"browser.blocking.domains":        "READER_BROWSER_BLOCKING_DOMAINS",
+		"browser.blocking.hosts_file":     "READER_BROWSER_BLOCKING_HOSTS_FILE",
+		"browser.consent.enabled":         "READER_BROWSER_CONSENT_ENABLED",
+		"browser.consent.action":          "READER_BROWSER_CONSENT_ACTION",
+		"browser.consent.wait":            "READER_BROWSER_CONSENT_WAIT",
+		"proxy.urls":                      "READER_PROXY_URLS",
+		"proxy.bypass":                    "READER_PROXY_BYPASS",
+		"proxy.max_failures":              "READER_PROXY_MAX_FAILURES",
+		"proxy.cooldown":                  "READER_PROXY_COOLDOWN",
+		"ai.enabled":                      "READER_AI_ENABLED",
+		"ai.provider":                     "READER_AI_PROVIDER",
+		"ai.api_endpoint":                 "READER_AI_ENDPOINT",
+		"ai.api_version":                  "READER_AI_API_VERSION",
+		"ai.api_key":                      "READER_AI_KEY",
+		"ai.model":                        "READER_AI_MODEL",
+		"ai.embedding_model":              "READER_AI_EMBEDDING_MODEL",
+		"ai.max_input_tokens":             "READER_AI_MAX_INPUT_TOKENS",
+		"ai.allow_custom_prompts":         "READER_AI_ALLOW_CUSTOM_PROMPTS",
+		"ai.concurrency":                  "READER_AI_CONCURRENCY",
+		"ai.json_mode":                    "READER_AI_JSON_MODE",
+		"ai.timeout":                      "READER_AI_TIMEOUT",
+		"ai.max_retries":                  "READER_AI_MAX_RETRIES",
+		"ai.breaker_threshold":            "READER_AI_BREAKER_THRESHOLD",
+		"ai.breaker_cooldown":             "READER_AI_BREAKER_COOLDOWN",
+		"ai.daily_token_budget":           "READER_AI_DAILY_TOKEN_BUDGET",
+		"redaction.enabled":               "READER_REDACTION_ENABLED",
+		"redaction.rules":                 "READER_REDACTION_RULES",
 	}
 
 	for configKey, envVar := range envs {
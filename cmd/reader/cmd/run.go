@@ -3,12 +3,14 @@ package cmd
 import (
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 
 	"github.com/ncecere/reader-go/internal/common/config"
 	"github.com/ncecere/reader-go/internal/common/logger"
 	"github.com/ncecere/reader-go/internal/core/ai"
 	"github.com/ncecere/reader-go/internal/core/browser"
+	"github.com/ncecere/reader-go/internal/core/redact"
 	"github.com/ncecere/reader-go/internal/core/service"
 	"github.com/ncecere/reader-go/internal/server"
 	"github.com/spf13/cobra"
@@ -21,11 +23,60 @@ var runCmd = &cobra.Command{
 	Short: "Run the reader server",
 	Long:  `Start the reader server with the specified configuration`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		blocking := config.BlockingConfig{
+			Enabled:       viper.GetBool("browser.blocking.enabled"),
+			ResourceTypes: splitList(viper.GetStringSlice("browser.blocking.resource_types")),
+			Domains:       splitList(viper.GetStringSlice("browser.blocking.domains")),
+			HostsFile:     viper.GetString("browser.blocking.hosts_file"),
+		}
+		if err := viper.UnmarshalKey("browser.blocking.sites", &blocking.Sites); err != nil {
+			logger.Log.Fatal("Failed to parse resource blocking sites", zap.Error(err))
+		}
+
+		consent := config.ConsentConfig{
+			Enabled:      viper.GetBool("browser.consent.enabled"),
+			Action:       viper.GetString("browser.consent.action"),
+			Wait:         viper.GetInt("browser.consent.wait"),
+			KeepOverlays: viper.GetBool("browser.consent.keep_overlays"),
+		}
+		if err := viper.UnmarshalKey("browser.consent.rules", &consent.Rules); err != nil {
+			logger.Log.Fatal("Failed to parse consent rules", zap.Error(err))
+		}
+
+		var sessions map[string]config.SessionConfig
+		if err := viper.UnmarshalKey("browser.sessions", &sessions); err != nil {
+			logger.Log.Fatal("Failed to parse browser sessions", zap.Error(err))
+		}
+
+		proxies := config.ProxyConfig{
+			URLs:        splitList(viper.GetStringSlice("proxy.urls")),
+			Bypass:      splitList(viper.GetStringSlice("proxy.bypass")),
+			MaxFailures: viper.GetInt("proxy.max_failures"),
+			Cooldown:    viper.GetInt("proxy.cooldown"),
+		}
+		if err := viper.UnmarshalKey("proxy.sites", &proxies.Sites); err != nil {
+			logger.Log.Fatal("Failed to parse proxy sites", zap.Error(err))
+		}
+
 		// Create browser service
 		browserService, err := service.NewService(&browser.BrowserOptions{
 			PoolSize:   viper.GetInt("browser.pool_size"),
+			Processes:  viper.GetInt("browser.processes"),
 			ChromePath: viper.GetString("browser.chrome_path"),
 			Timeout:    viper.GetInt("browser.timeout"),
+
+			MaxUses:             viper.GetInt("browser.max_uses"),
+			MaxAge:              viper.GetInt("browser.max_age"),
+			HealthCheckInterval: viper.GetInt("browser.health_check_interval"),
+			MemoryLimitMB:       viper.GetInt("browser.memory_limit"),
+			MaxQueue:            viper.GetInt("browser.max_queue"),
+			QueueTimeout:        viper.GetInt("browser.queue_timeout"),
+			MaxScrolls:          viper.GetInt("browser.scroll.max_scrolls"),
+			ScrollTimeout:       viper.GetInt("browser.scroll.timeout"),
+			Blocking:            blocking,
+			Consent:             consent,
+			Sessions:            sessions,
+			Proxy:               proxies,
 		})
 		if err != nil {
 			logger.Log.Fatal("Failed to create browser service", zap.Error(err))
@@ -33,11 +84,49 @@ var runCmd = &cobra.Command{
 
 		// Create config for AI service
 		cfg := &config.Config{}
+		cfg.Proxy = proxies
 		cfg.AI.Enabled = viper.GetBool("ai.enabled")
+		cfg.AI.Provider = viper.GetString("ai.provider")
+		cfg.AI.APIVersion = viper.GetString("ai.api_version")
 		cfg.AI.APIEndpoint = viper.GetString("ai.api_endpoint")
 		cfg.AI.APIKey = viper.GetString("ai.api_key")
 		cfg.AI.Model = viper.GetString("ai.model")
 		cfg.AI.Prompt = viper.GetString("ai.prompt")
+		cfg.AI.Prompts = viper.GetStringMapString("ai.prompts")
+		cfg.AI.AllowCustomPrompts = viper.GetBool("ai.allow_custom_prompts")
+		cfg.AI.EmbeddingModel = viper.GetString("ai.embedding_model")
+		cfg.AI.MaxInputTokens = viper.GetInt("ai.max_input_tokens")
+		cfg.AI.Concurrency = viper.GetInt("ai.concurrency")
+		cfg.AI.JSONMode = viper.GetBool("ai.json_mode")
+		if err := viper.UnmarshalKey("ai.providers", &cfg.AI.Providers); err != nil {
+			logger.Log.Fatal("Failed to parse AI providers", zap.Error(err))
+		}
+		cfg.AI.Timeout = viper.GetInt("ai.timeout")
+		cfg.AI.MaxRetries = viper.GetInt("ai.max_retries")
+		cfg.AI.BreakerThreshold = viper.GetInt("ai.breaker_threshold")
+		cfg.AI.BreakerCooldown = viper.GetInt("ai.breaker_cooldown")
+		if err := viper.UnmarshalKey("ai.fallbacks", &cfg.AI.Fallbacks); err != nil {
+			logger.Log.Fatal("Failed to parse AI fallbacks", zap.Error(err))
+		}
+		if err := viper.UnmarshalKey("ai.prices", &cfg.AI.Prices); err != nil {
+			logger.Log.Fatal("Failed to parse AI prices", zap.Error(err))
+		}
+		cfg.AI.DailyTokenBudget = viper.GetInt("ai.daily_token_budget")
+		if err := viper.UnmarshalKey("ai.key_budgets", &cfg.AI.KeyBudgets); err != nil {
+			logger.Log.Fatal("Failed to parse AI key budgets", zap.Error(err))
+		}
+
+		cfg.Redaction.Enabled = viper.GetBool("redaction.enabled")
+		cfg.Redaction.Rules = splitList(viper.GetStringSlice("redaction.rules"))
+		if err := viper.UnmarshalKey("redaction.custom", &cfg.Redaction.Custom); err != nil {
+			logger.Log.Fatal("Failed to parse custom redaction rules", zap.Error(err))
+		}
+
+		// Create redactor for X-Redact output
+		redactor, err := redact.New(cfg.Redaction.Rules, cfg.Redaction.Custom)
+		if err != nil {
+			logger.Log.Fatal("Invalid redaction configuration", zap.Error(err))
+		}
 
 		// Create AI service
 		aiService := ai.NewService(cfg)
@@ -45,7 +134,7 @@ var runCmd = &cobra.Command{
 		// Create and start server
 		srv := server.New(&server.Config{
 			Port: viper.GetInt("server.port"),
-		}, browserService, aiService)
+		}, browserService, aiService, redactor)
 
 		// Handle shutdown gracefully
 		go func() {
@@ -73,3 +162,17 @@ var runCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(runCmd)
 }
+
+// splitList flattens comma-separated entries, so list settings can also be given
+// as "a,b" in environment variables
+func splitList(values []string) []string {
+	var list []string
+	for _, v := range values {
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+	}
+	return list
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/ncecere/reader-go/internal/common/logger"
+	"github.com/ncecere/reader-go/internal/common/metrics"
+	"github.com/ncecere/reader-go/internal/core/ai"
+	"github.com/ncecere/reader-go/internal/core/service"
+	"go.uber.org/zap"
+)
+
+// AskHandler handles question answering over a page
+type AskHandler struct {
+	browser *service.Service
+	ai      *ai.Service
+}
+
+// AskRequest is the JSON body accepted by POST /ask
+type AskRequest struct {
+	URL      string `json:"url"`
+	Question string `json:"question"`
+}
+
+// AskResponse is the JSON body returned by POST /ask
+type AskResponse struct {
+	URL      string    `json:"url"`
+	Question string    `json:"question"`
+	Answer   string    `json:"answer"`
+	Quotes   []string  `json:"quotes"`
+	Usage    *ai.Usage `json:"usage,omitempty"`
+}
+
+// NewAskHandler creates a new ask handler
+func NewAskHandler(browser *service.Service, ai *ai.Service) *AskHandler {
+	return &AskHandler{
+		browser: browser,
+		ai:      ai,
+	}
+}
+
+// HandleRequest answers a question using the content of the requested page
+func (h *AskHandler) HandleRequest(c *fiber.Ctx) error {
+	var req AskRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).SendString("Invalid request body")
+	}
+	req.URL = strings.TrimSpace(req.URL)
+	req.Question = strings.TrimSpace(req.Question)
+	if req.URL == "" || req.Question == "" {
+		return c.Status(400).SendString("Both url and question are required")
+	}
+
+	// Start timing
+	start := time.Now()
+	defer func() {
+		duration := time.Since(start).Seconds()
+		metrics.ContentProcessingDuration.WithLabelValues("ask").Observe(duration)
+	}()
+
+	text, err := h.browser.GetText(c.UserContext(), req.URL)
+	if err != nil {
+		logger.Log.Error("Failed to extract text for question",
+			zap.String("url", req.URL),
+			zap.Error(err))
+		metrics.ContentProcessingErrors.WithLabelValues("ask", "extraction_failed").Inc()
+		return browserFailure(c, err, "Failed to extract text for question")
+	}
+
+	answer, err := h.ai.Ask(c.UserContext(), req.Question, text)
+	if err != nil {
+		logger.Log.Error("Failed to answer question",
+			zap.String("url", req.URL),
+			zap.Error(err))
+		metrics.ContentProcessingErrors.WithLabelValues("ask", "answer_failed").Inc()
+		return c.SendString("Failed to answer question")
+	}
+
+	// Record metrics
+	domain := extractDomain(req.URL)
+	metrics.URLProcessing.WithLabelValues(domain).Inc()
+	metrics.URLContentTypes.WithLabelValues("ask").Inc()
+
+	return c.JSON(AskResponse{
+		URL:      req.URL,
+		Question: req.Question,
+		Answer:   answer.Answer,
+		Quotes:   answer.Quotes,
+		Usage:    requestUsage(c),
+	})
+}
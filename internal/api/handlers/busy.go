@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/ncecere/reader-go/internal/core/browser"
+)
+
+// busyRetryAfter is the Retry-After, in seconds, sent when the browser pool is saturated
+const busyRetryAfter = 5
+
+// browserFailure responds to a failed page fetch. When the browser pool is saturated the
+// client gets 503 with Retry-After so it backs off; a failed page action gets 422 naming
+// the step; other failures send msg.
+func browserFailure(c *fiber.Ctx, err error, msg string) error {
+	if errors.Is(err, browser.ErrPoolBusy) {
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(busyRetryAfter))
+		return c.Status(fiber.StatusServiceUnavailable).SendString("Browser pool is busy, retry later")
+	}
+	var actionErr *browser.ActionError
+	if errors.As(err, &actionErr) {
+		return c.Status(fiber.StatusUnprocessableEntity).SendString(actionErr.Error())
+	}
+	return c.SendString(msg)
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/ncecere/reader-go/internal/core/chunker"
+)
+
+// ChunksResponse is the JSON body returned for X-Respond-With: chunks
+type ChunksResponse struct {
+	URL    string          `json:"url"`
+	Title  string          `json:"title,omitempty"`
+	Unit   chunker.Unit    `json:"unit"`
+	Size   int             `json:"size"`
+	Chunks []chunker.Chunk `json:"chunks"`
+}
+
+// parseChunkOptions reads X-Chunk-Size, X-Chunk-Overlap and X-Chunk-Unit,
+// falling back to the chunker defaults for any header that is not set. Without
+// X-Chunk-Overlap the overlap is an eighth of the size, as in the defaults.
+func parseChunkOptions(c *fiber.Ctx) (*chunker.Options, error) {
+	opts := chunker.DefaultOptions()
+
+	if unit := c.Get("X-Chunk-Unit"); unit != "" {
+		opts.Unit = chunker.Unit(unit)
+	}
+	if opts.Unit == chunker.Chars {
+		// Keep the default budget roughly the same when measuring in characters
+		opts.Size *= chunker.CharsPerToken
+	}
+
+	var err error
+	if opts.Size, err = chunkHeader(c, "X-Chunk-Size", opts.Size); err != nil {
+		return nil, err
+	}
+	if opts.Overlap, err = chunkHeader(c, "X-Chunk-Overlap", opts.Size/8); err != nil {
+		return nil, err
+	}
+
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	return opts, nil
+}
+
+// chunkHeader returns the integer value of header, or def if it is not set
+func chunkHeader(c *fiber.Ctx, header string, def int) (int, error) {
+	raw := c.Get(header)
+	if raw == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %q", header, raw)
+	}
+	return n, nil
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/ncecere/reader-go/internal/core/chunker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// chunkOptions runs parseChunkOptions on a request with headers
+func chunkOptions(t *testing.T, headers map[string]string) (*chunker.Options, error) {
+	var (
+		opts *chunker.Options
+		err  error
+	)
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		opts, err = parseChunkOptions(c)
+		return nil
+	})
+	req := httptest.NewRequest("GET", "/", nil)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	_, testErr := app.Test(req)
+	require.NoError(t, testErr)
+	return opts, err
+}
+
+func TestParseChunkOptions(t *testing.T) {
+	opts, err := chunkOptions(t, nil)
+	require.NoError(t, err)
+	assert.Equal(t, chunker.DefaultOptions(), opts)
+
+	opts, err = chunkOptions(t, map[string]string{"X-Chunk-Size": "64"})
+	require.NoError(t, err)
+	assert.Equal(t, 64, opts.Size)
+	assert.Equal(t, 8, opts.Overlap, "the overlap follows the size")
+	_, err = chunker.Split("# Title\n\nText.", "https://example.com", opts)
+	assert.NoError(t, err)
+
+	opts, err = chunkOptions(t, map[string]string{"X-Chunk-Unit": "chars", "X-Chunk-Size": "200"})
+	require.NoError(t, err)
+	assert.Equal(t, chunker.Chars, opts.Unit)
+	assert.Equal(t, 25, opts.Overlap)
+
+	opts, err = chunkOptions(t, map[string]string{"X-Chunk-Unit": "chars"})
+	require.NoError(t, err)
+	assert.Equal(t, 512*chunker.CharsPerToken, opts.Size)
+	assert.Equal(t, 64*chunker.CharsPerToken, opts.Overlap)
+
+	opts, err = chunkOptions(t, map[string]string{"X-Chunk-Size": "100", "X-Chunk-Overlap": "0"})
+	require.NoError(t, err)
+	assert.Zero(t, opts.Overlap, "an explicit overlap is kept")
+
+	_, err = chunkOptions(t, map[string]string{"X-Chunk-Unit": "chars", "X-Chunk-Size": "1"})
+	assert.ErrorContains(t, err, "chunk size must be at least", "tiny chunks are rejected")
+
+	_, err = chunkOptions(t, map[string]string{"X-Chunk-Overlap": "some"})
+	assert.ErrorContains(t, err, "X-Chunk-Overlap")
+}
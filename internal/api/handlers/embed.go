@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/ncecere/reader-go/internal/common/logger"
+	"github.com/ncecere/reader-go/internal/common/metrics"
+	"github.com/ncecere/reader-go/internal/core/ai"
+	"github.com/ncecere/reader-go/internal/core/chunker"
+	"github.com/ncecere/reader-go/internal/core/converter"
+	"github.com/ncecere/reader-go/internal/core/service"
+	"go.uber.org/zap"
+)
+
+// EmbedHandler handles page embedding requests
+type EmbedHandler struct {
+	browser *service.Service
+	ai      *ai.Service
+}
+
+// EmbeddedChunk is a chunk together with its embedding vector
+type EmbeddedChunk struct {
+	chunker.Chunk
+	Embedding []float64 `json:"embedding"`
+}
+
+// EmbedResponse is the JSON body returned by /embed
+type EmbedResponse struct {
+	URL    string          `json:"url"`
+	Model  string          `json:"model"`
+	Chunks []EmbeddedChunk `json:"chunks"`
+	Usage  *ai.Usage       `json:"usage,omitempty"`
+}
+
+// NewEmbedHandler creates a new embed handler
+func NewEmbedHandler(browser *service.Service, ai *ai.Service) *EmbedHandler {
+	return &EmbedHandler{
+		browser: browser,
+		ai:      ai,
+	}
+}
+
+// HandleRequest extracts and chunks a page, then returns an embedding for every chunk
+func (h *EmbedHandler) HandleRequest(c *fiber.Ctx) error {
+	url := strings.TrimPrefix(c.Path(), "/embed/")
+
+	// Start timing
+	start := time.Now()
+	defer func() {
+		duration := time.Since(start).Seconds()
+		metrics.ContentProcessingDuration.WithLabelValues("embed").Observe(duration)
+	}()
+
+	opts, err := parseChunkOptions(c)
+	if err != nil {
+		return c.Status(400).SendString(err.Error())
+	}
+
+	html, err := h.browser.GetHTML(c.UserContext(), url)
+	if err != nil {
+		logger.Log.Error("Failed to get HTML for embedding",
+			zap.String("url", url),
+			zap.Error(err))
+		metrics.ContentProcessingErrors.WithLabelValues("embed", "html_extraction_failed").Inc()
+		return browserFailure(c, err, "Failed to get HTML for embedding")
+	}
+
+	markdown, err := converter.ConvertHTML(html)
+	if err != nil {
+		logger.Log.Error("Failed to convert to markdown",
+			zap.String("url", url),
+			zap.Error(err))
+		metrics.ContentProcessingErrors.WithLabelValues("embed", "conversion_failed").Inc()
+		return c.SendString("Failed to convert to markdown")
+	}
+
+	chunks, err := chunker.Split(markdown, url, opts)
+	if err != nil {
+		return c.Status(400).SendString(err.Error())
+	}
+
+	texts := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		texts[i] = chunk.Text
+	}
+
+	vectors, err := h.ai.Embed(c.UserContext(), texts)
+	if err != nil {
+		logger.Log.Error("Failed to generate embeddings",
+			zap.String("url", url),
+			zap.Error(err))
+		metrics.ContentProcessingErrors.WithLabelValues("embed", "embedding_failed").Inc()
+		return c.SendString("Failed to generate embeddings")
+	}
+
+	resp := EmbedResponse{
+		URL:    url,
+		Model:  h.ai.EmbeddingModel(c.UserContext()),
+		Chunks: make([]EmbeddedChunk, len(chunks)),
+		Usage:  requestUsage(c),
+	}
+	for i, chunk := range chunks {
+		resp.Chunks[i] = EmbeddedChunk{Chunk: chunk, Embedding: vectors[i]}
+	}
+
+	// Record metrics
+	domain := extractDomain(url)
+	metrics.URLProcessing.WithLabelValues(domain).Inc()
+	metrics.URLContentTypes.WithLabelValues("embed").Inc()
+
+	return c.JSON(resp)
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/ncecere/reader-go/internal/common/logger"
+	"github.com/ncecere/reader-go/internal/common/metrics"
+	"github.com/ncecere/reader-go/internal/core/ai"
+	"github.com/ncecere/reader-go/internal/core/schema"
+	"github.com/ncecere/reader-go/internal/core/service"
+	"go.uber.org/zap"
+)
+
+// ExtractHandler handles schema-driven structured data extraction
+type ExtractHandler struct {
+	browser *service.Service
+	ai      *ai.Service
+}
+
+// ExtractRequest is the JSON body accepted by POST /extract
+type ExtractRequest struct {
+	URL    string          `json:"url"`
+	Schema json.RawMessage `json:"schema"`
+}
+
+// ExtractResponse is the JSON body returned by POST /extract
+type ExtractResponse struct {
+	URL   string          `json:"url"`
+	Data  json.RawMessage `json:"data"`
+	Usage *ai.Usage       `json:"usage,omitempty"`
+}
+
+// ExtractErrorResponse is returned when the model's output does not match the schema
+type ExtractErrorResponse struct {
+	Error  string                   `json:"error"`
+	Data   json.RawMessage          `json:"data,omitempty"`
+	Errors []schema.ValidationError `json:"validation_errors"`
+	Usage  *ai.Usage                `json:"usage,omitempty"`
+}
+
+// NewExtractHandler creates a new extract handler
+func NewExtractHandler(browser *service.Service, ai *ai.Service) *ExtractHandler {
+	return &ExtractHandler{
+		browser: browser,
+		ai:      ai,
+	}
+}
+
+// HandleRequest extracts data matching the request's JSON Schema from the requested page
+func (h *ExtractHandler) HandleRequest(c *fiber.Ctx) error {
+	var req ExtractRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).SendString("Invalid request body")
+	}
+	req.URL = strings.TrimSpace(req.URL)
+	if req.URL == "" || len(req.Schema) == 0 {
+		return c.Status(400).SendString("Both url and schema are required")
+	}
+	if _, err := schema.Parse(req.Schema); err != nil {
+		return c.Status(400).SendString(err.Error())
+	}
+
+	// Start timing
+	start := time.Now()
+	defer func() {
+		duration := time.Since(start).Seconds()
+		metrics.ContentProcessingDuration.WithLabelValues("extract").Observe(duration)
+	}()
+
+	text, err := h.browser.GetText(c.UserContext(), req.URL)
+	if err != nil {
+		logger.Log.Error("Failed to extract text for structured extraction",
+			zap.String("url", req.URL),
+			zap.Error(err))
+		metrics.ContentProcessingErrors.WithLabelValues("extract", "extraction_failed").Inc()
+		return browserFailure(c, err, "Failed to extract text for structured extraction")
+	}
+
+	data, err := h.ai.Extract(c.UserContext(), text, req.Schema)
+	if err != nil {
+		var extractionErr *ai.ExtractionError
+		if errors.As(err, &extractionErr) {
+			metrics.ContentProcessingErrors.WithLabelValues("extract", "schema_mismatch").Inc()
+			return c.Status(422).JSON(ExtractErrorResponse{
+				Error:  "Extracted data does not match schema",
+				Data:   extractionErr.Data,
+				Errors: extractionErr.Errors,
+				Usage:  requestUsage(c),
+			})
+		}
+		logger.Log.Error("Failed to extract structured data",
+			zap.String("url", req.URL),
+			zap.Error(err))
+		metrics.ContentProcessingErrors.WithLabelValues("extract", "ai_failed").Inc()
+		return c.SendString("Failed to extract structured data")
+	}
+
+	// Record metrics
+	domain := extractDomain(req.URL)
+	metrics.URLProcessing.WithLabelValues(domain).Inc()
+	metrics.URLContentTypes.WithLabelValues("extract").Inc()
+
+	return c.JSON(ExtractResponse{
+		URL:   req.URL,
+		Data:  data,
+		Usage: requestUsage(c),
+	})
+}
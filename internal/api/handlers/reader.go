@@ -1,32 +1,88 @@
 package handlers
 
 import (
+	"encoding/json"
 	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/ncecere/reader-go/internal/common/logger"
 	"github.com/ncecere/reader-go/internal/common/metrics"
+	"github.com/ncecere/reader-go/internal/core/browser"
+	"github.com/ncecere/reader-go/internal/core/chunker"
 	"github.com/ncecere/reader-go/internal/core/converter"
+	"github.com/ncecere/reader-go/internal/core/metadata"
+	"github.com/ncecere/reader-go/internal/core/redact"
 	"github.com/ncecere/reader-go/internal/core/service"
+	"github.com/ncecere/reader-go/internal/core/tables"
 	"go.uber.org/zap"
 )
 
 // ReaderHandler handles web content extraction requests
 type ReaderHandler struct {
-	browser *service.Service
+	browser  *service.Service
+	redactor *redact.Redactor
 }
 
-// NewReaderHandler creates a new reader handler
-func NewReaderHandler(browser *service.Service) *ReaderHandler {
+// NewReaderHandler creates a new reader handler. The redactor serves X-Redact requests.
+func NewReaderHandler(browser *service.Service, redactor *redact.Redactor) *ReaderHandler {
 	return &ReaderHandler{
-		browser: browser,
+		browser:  browser,
+		redactor: redactor,
 	}
 }
 
+// ReaderRequest is the JSON body accepted by POST /, which runs actions on the page
+// before it is read and may set cookies, headers and basic auth credentials for it.
+// The URL may also be given in the path as for GET requests.
+type ReaderRequest struct {
+	URL     string           `json:"url"`
+	Actions []browser.Action `json:"actions"`
+	browser.Session
+}
+
 // HandleRequest processes URL requests and returns content in requested format
 func (h *ReaderHandler) HandleRequest(c *fiber.Ctx) error {
-	url := strings.TrimPrefix(c.Path(), "/")
+	return h.respond(c, strings.TrimPrefix(c.Path(), "/"))
+}
+
+// HandleActions runs the request's actions on the page, e.g. logging in or clicking
+// "show more", and then returns its content like HandleRequest
+func (h *ReaderHandler) HandleActions(c *fiber.Ctx) error {
+	var req ReaderRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).SendString("Invalid request body")
+	}
+	url := strings.TrimSpace(req.URL)
+	if url == "" {
+		url = strings.TrimPrefix(c.Path(), "/")
+	}
+	if url == "" {
+		return c.Status(400).SendString("url is required")
+	}
+	if err := browser.ValidateActions(req.Actions); err != nil {
+		return c.Status(400).SendString(err.Error())
+	}
+
+	for _, cookie := range req.Cookies {
+		if cookie.Name == "" {
+			return c.Status(400).SendString("cookies need a name")
+		}
+	}
+
+	if len(req.Actions) > 0 {
+		c.SetUserContext(browser.WithActions(c.UserContext(), req.Actions))
+	}
+	session := req.Session
+	if header := browser.SessionFrom(c.UserContext()); header != nil {
+		session.Cookies = append(header.Cookies, session.Cookies...)
+	}
+	c.SetUserContext(browser.WithSession(c.UserContext(), &session))
+	return h.respond(c, url)
+}
+
+// respond fetches url and returns its content in the format named by X-Respond-With
+func (h *ReaderHandler) respond(c *fiber.Ctx, url string) error {
 	format := c.Get("X-Respond-With", "text")
 
 	// Start timing
@@ -43,23 +99,32 @@ func (h *ReaderHandler) HandleRequest(c *fiber.Ctx) error {
 
 	switch format {
 	case "text":
-		content, err = h.browser.GetText(c.Context(), url)
+		// Metadata is read from the same page load as the text
+		var meta *metadata.Metadata
+		if c.Get("X-With-Metadata") == "true" {
+			content, meta, err = h.browser.GetTextWithMetadata(c.UserContext(), url)
+		} else {
+			content, err = h.browser.GetText(c.UserContext(), url)
+		}
 		if err != nil {
 			logger.Log.Error("Failed to extract text",
 				zap.String("url", url),
 				zap.Error(err))
 			metrics.ContentProcessingErrors.WithLabelValues(format, "extraction_failed").Inc()
-			return c.SendString("Failed to extract text")
+			return browserFailure(c, err, "Failed to extract text")
+		}
+		if meta != nil {
+			content = converter.MetadataHeader(meta) + "\n" + content
 		}
 
 	case "markdown":
-		html, err := h.browser.GetHTML(c.Context(), url)
+		html, err := h.browser.GetHTML(c.UserContext(), url)
 		if err != nil {
 			logger.Log.Error("Failed to get HTML",
 				zap.String("url", url),
 				zap.Error(err))
 			metrics.ContentProcessingErrors.WithLabelValues(format, "html_extraction_failed").Inc()
-			return c.SendString("Failed to get HTML")
+			return browserFailure(c, err, "Failed to get HTML")
 		}
 
 		content, err = converter.HTMLToMarkdown(html)
@@ -71,10 +136,108 @@ func (h *ReaderHandler) HandleRequest(c *fiber.Ctx) error {
 			return c.SendString("Failed to convert to markdown")
 		}
 
+	case "metadata":
+		meta, err := h.browser.GetMetadata(c.UserContext(), url)
+		if err != nil {
+			logger.Log.Error("Failed to extract metadata",
+				zap.String("url", url),
+				zap.Error(err))
+			metrics.ContentProcessingErrors.WithLabelValues(format, "extraction_failed").Inc()
+			return browserFailure(c, err, "Failed to extract metadata")
+		}
+
+		data, err := json.Marshal(meta)
+		if err != nil {
+			metrics.ContentProcessingErrors.WithLabelValues(format, "conversion_failed").Inc()
+			return c.SendString("Failed to encode metadata")
+		}
+		content = string(data)
+		c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	case "tables":
+		tableFormat := c.Get("X-Table-Format", "json")
+		if tableFormat != "json" && tableFormat != "csv" {
+			return c.Status(400).SendString("Invalid table format")
+		}
+
+		html, err := h.browser.GetHTML(c.UserContext(), url)
+		if err != nil {
+			logger.Log.Error("Failed to get HTML",
+				zap.String("url", url),
+				zap.Error(err))
+			metrics.ContentProcessingErrors.WithLabelValues(format, "html_extraction_failed").Inc()
+			return browserFailure(c, err, "Failed to get HTML")
+		}
+
+		found, err := tables.Extract(html)
+		if err != nil {
+			logger.Log.Error("Failed to extract tables",
+				zap.String("url", url),
+				zap.Error(err))
+			metrics.ContentProcessingErrors.WithLabelValues(format, "extraction_failed").Inc()
+			return c.SendString("Failed to extract tables")
+		}
+
+		content, err = encodeTables(c, found, tableFormat)
+		if err != nil {
+			logger.Log.Error("Failed to encode tables",
+				zap.String("url", url),
+				zap.Error(err))
+			metrics.ContentProcessingErrors.WithLabelValues(format, "conversion_failed").Inc()
+			return c.SendString("Failed to encode tables")
+		}
+
+	case "chunks":
+		opts, err := parseChunkOptions(c)
+		if err != nil {
+			return c.Status(400).SendString(err.Error())
+		}
+
+		html, err := h.browser.GetHTML(c.UserContext(), url)
+		if err != nil {
+			logger.Log.Error("Failed to get HTML",
+				zap.String("url", url),
+				zap.Error(err))
+			metrics.ContentProcessingErrors.WithLabelValues(format, "html_extraction_failed").Inc()
+			return browserFailure(c, err, "Failed to get HTML")
+		}
+
+		markdown, err := converter.ConvertHTML(html)
+		if err != nil {
+			logger.Log.Error("Failed to convert to markdown",
+				zap.String("url", url),
+				zap.Error(err))
+			metrics.ContentProcessingErrors.WithLabelValues(format, "conversion_failed").Inc()
+			return c.SendString("Failed to convert to markdown")
+		}
+
+		chunks, err := chunker.Split(markdown, url, opts)
+		if err != nil {
+			return c.Status(400).SendString(err.Error())
+		}
+
+		resp := ChunksResponse{URL: url, Unit: opts.Unit, Size: opts.Size, Chunks: chunks}
+		if meta, err := metadata.Extract(html); err == nil {
+			resp.Title = meta.Title
+		}
+
+		data, err := json.Marshal(resp)
+		if err != nil {
+			metrics.ContentProcessingErrors.WithLabelValues(format, "conversion_failed").Inc()
+			return c.SendString("Failed to encode chunks")
+		}
+		content = string(data)
+		c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
 	default:
 		return c.Status(400).SendString("Invalid format")
 	}
 
+	// Masking only makes sense for prose; JSON formats would lose their structure
+	if c.Get("X-Redact") == "true" && (format == "text" || format == "markdown") {
+		content = h.redact(c, content)
+	}
+
 	// Record content size
 	metrics.ContentSize.WithLabelValues(format).Observe(float64(len(content)))
 
@@ -87,6 +250,31 @@ func (h *ReaderHandler) HandleRequest(c *fiber.Ctx) error {
 	return c.SendString(content)
 }
 
+// redact masks sensitive data in content and reports the counts in X-Redactions
+func (h *ReaderHandler) redact(c *fiber.Ctx, content string) string {
+	content, counts := h.redactor.Redact(content)
+	for rule, n := range counts {
+		metrics.Redactions.WithLabelValues(rule, "output").Add(float64(n))
+	}
+	c.Set("X-Redactions", counts.String())
+	return content
+}
+
+// encodeTables renders tables as JSON or CSV and sets the matching content type
+func encodeTables(c *fiber.Ctx, found []tables.Table, tableFormat string) (string, error) {
+	if tableFormat == "csv" {
+		c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+		return tables.ToCSVDocument(found)
+	}
+
+	data, err := tables.ToJSON(found)
+	if err != nil {
+		return "", err
+	}
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	return string(data), nil
+}
+
 // extractDomain extracts the domain from a URL
 func extractDomain(url string) string {
 	// Simple domain extraction
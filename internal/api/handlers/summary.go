@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"strings"
 	"time"
 
@@ -39,18 +41,38 @@ func (h *SummaryHandler) HandleRequest(c *fiber.Ctx) error {
 		metrics.ContentProcessingDuration.WithLabelValues("summary").Observe(duration)
 	}()
 
+	if format != "text" && format != "markdown" {
+		return c.Status(400).SendString("Invalid format")
+	}
+
+	opts := &ai.SummaryOptions{
+		Style:       c.Get("X-Summary-Style"),
+		Instruction: c.Get("X-Summary-Instruction"),
+	}
+	if err := h.ai.ValidateSummaryOptions(opts); err != nil {
+		if errors.Is(err, ai.ErrCustomPromptDenied) {
+			return c.Status(403).SendString(err.Error())
+		}
+		return c.Status(400).SendString(fmt.Sprintf("%v (available: %s)",
+			err, strings.Join(h.ai.Styles(), ", ")))
+	}
+
 	// Get the text content first
-	text, err := h.browser.GetText(c.Context(), url)
+	text, err := h.browser.GetText(c.UserContext(), url)
 	if err != nil {
 		logger.Log.Error("Failed to extract text for summary",
 			zap.String("url", url),
 			zap.Error(err))
 		metrics.ContentProcessingErrors.WithLabelValues("summary", "extraction_failed").Inc()
-		return c.SendString("Failed to extract text for summary")
+		return browserFailure(c, err, "Failed to extract text for summary")
+	}
+
+	if wantsStream(c) {
+		return h.streamSummary(c, url, text, format, opts)
 	}
 
 	// Generate summary
-	summary, err := h.ai.Summarize(c.Context(), text)
+	summary, err := h.ai.Summarize(c.UserContext(), text, opts)
 	if err != nil {
 		logger.Log.Error("Failed to generate summary",
 			zap.String("url", url),
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/ncecere/reader-go/internal/common/logger"
+	"github.com/ncecere/reader-go/internal/common/metrics"
+	"github.com/ncecere/reader-go/internal/core/ai"
+	"github.com/ncecere/reader-go/internal/core/converter"
+	"go.uber.org/zap"
+)
+
+// streamTimeout bounds how long a streamed summary may run after the handler returns
+const streamTimeout = 5 * time.Minute
+
+// wantsStream reports whether the client asked for a server-sent event stream
+func wantsStream(c *fiber.Ctx) bool {
+	return c.Get("X-Stream") == "true" || strings.Contains(c.Get(fiber.HeaderAccept), "text/event-stream")
+}
+
+// streamSummary relays summary tokens to the client as server-sent events.
+// Each event carries {"delta": "..."}; the stream ends with "data: [DONE]" or an "error" event.
+func (h *SummaryHandler) streamSummary(c *fiber.Ctx, url, text, format string, opts *ai.SummaryOptions) error {
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	// The request is released once the handler returns, so keep only its user context
+	userCtx := c.UserContext()
+	tracker := ai.UsageTrackerFrom(userCtx)
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		ctx, cancel := context.WithTimeout(userCtx, streamTimeout)
+		defer cancel()
+		if tracker != nil {
+			// The usage middleware has returned without releasing the budget reservation
+			defer h.ai.ReleaseBudget(tracker)
+		}
+
+		send := func(event string, payload interface{}) error {
+			data, err := json.Marshal(payload)
+			if err != nil {
+				return err
+			}
+			if event != "" {
+				fmt.Fprintf(w, "event: %s\n", event)
+			}
+			fmt.Fprintf(w, "data: %s\n\n", data)
+			return w.Flush()
+		}
+
+		if format == "markdown" {
+			header, _ := converter.TextToMarkdown("")
+			if err := send("", fiber.Map{"delta": header}); err != nil {
+				return
+			}
+		}
+
+		summary, err := h.ai.SummarizeStream(ctx, text, opts, func(delta string) error {
+			return send("", fiber.Map{"delta": delta})
+		})
+		if err != nil {
+			logger.Log.Error("Failed to stream summary",
+				zap.String("url", url),
+				zap.Error(err))
+			metrics.ContentProcessingErrors.WithLabelValues("summary", "summarization_failed").Inc()
+			_ = send("error", fiber.Map{"error": "Failed to generate summary"})
+			return
+		}
+
+		// Record metrics
+		metrics.ContentSize.WithLabelValues("summary").Observe(float64(len(summary)))
+		domain := extractDomain(url)
+		metrics.URLProcessing.WithLabelValues(domain).Inc()
+		metrics.URLContentTypes.WithLabelValues("summary").Inc()
+		metrics.URLSizes.WithLabelValues(domain).Observe(float64(len(summary)))
+
+		if tracker != nil {
+			_ = send("", fiber.Map{"usage": tracker.Usage()})
+		}
+
+		fmt.Fprint(w, "data: [DONE]\n\n")
+		_ = w.Flush()
+	})
+
+	return nil
+}
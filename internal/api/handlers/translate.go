@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/ncecere/reader-go/internal/common/logger"
+	"github.com/ncecere/reader-go/internal/common/metrics"
+	"github.com/ncecere/reader-go/internal/core/ai"
+	"github.com/ncecere/reader-go/internal/core/converter"
+	"github.com/ncecere/reader-go/internal/core/langdetect"
+	"github.com/ncecere/reader-go/internal/core/metadata"
+	"github.com/ncecere/reader-go/internal/core/service"
+	"go.uber.org/zap"
+)
+
+// TranslateHandler handles page translation requests
+type TranslateHandler struct {
+	browser *service.Service
+	ai      *ai.Service
+}
+
+// NewTranslateHandler creates a new translate handler
+func NewTranslateHandler(browser *service.Service, ai *ai.Service) *TranslateHandler {
+	return &TranslateHandler{
+		browser: browser,
+		ai:      ai,
+	}
+}
+
+// HandleRequest returns the page's markdown translated into X-Target-Lang
+func (h *TranslateHandler) HandleRequest(c *fiber.Ctx) error {
+	url := strings.TrimPrefix(c.Path(), "/translate/")
+	targetLang := strings.TrimSpace(c.Get("X-Target-Lang"))
+	if targetLang == "" {
+		return c.Status(400).SendString("X-Target-Lang header is required")
+	}
+
+	// Start timing
+	start := time.Now()
+	defer func() {
+		duration := time.Since(start).Seconds()
+		metrics.ContentProcessingDuration.WithLabelValues("translate").Observe(duration)
+	}()
+
+	html, err := h.browser.GetHTML(c.UserContext(), url)
+	if err != nil {
+		logger.Log.Error("Failed to get HTML for translation",
+			zap.String("url", url),
+			zap.Error(err))
+		metrics.ContentProcessingErrors.WithLabelValues("translate", "html_extraction_failed").Inc()
+		return browserFailure(c, err, "Failed to get HTML")
+	}
+
+	markdown, err := converter.ConvertHTML(html)
+	if err != nil {
+		logger.Log.Error("Failed to convert to markdown",
+			zap.String("url", url),
+			zap.Error(err))
+		metrics.ContentProcessingErrors.WithLabelValues("translate", "conversion_failed").Inc()
+		return c.SendString("Failed to convert to markdown")
+	}
+
+	// Prefer the page's declared language, falling back to detection from the content
+	var sourceLang string
+	if meta, err := metadata.Extract(html); err == nil {
+		sourceLang = langdetect.Normalize(meta.Lang)
+	}
+	if sourceLang == "" {
+		sourceLang = langdetect.Detect(markdown)
+	}
+
+	translated, err := h.ai.Translate(c.UserContext(), markdown, sourceLang, targetLang)
+	if err != nil {
+		if errors.Is(err, ai.ErrInvalidLanguage) {
+			return c.Status(400).SendString(err.Error())
+		}
+		logger.Log.Error("Failed to translate content",
+			zap.String("url", url),
+			zap.String("target_lang", targetLang),
+			zap.Error(err))
+		metrics.ContentProcessingErrors.WithLabelValues("translate", "translation_failed").Inc()
+		return c.SendString("Failed to translate content")
+	}
+
+	// Record metrics
+	metrics.ContentSize.WithLabelValues("translate").Observe(float64(len(translated)))
+	domain := extractDomain(url)
+	metrics.URLProcessing.WithLabelValues(domain).Inc()
+	metrics.URLContentTypes.WithLabelValues("translate").Inc()
+	metrics.URLSizes.WithLabelValues(domain).Observe(float64(len(translated)))
+
+	if sourceLang != "" {
+		c.Set("X-Source-Lang", sourceLang)
+	}
+	c.Set(fiber.HeaderContentLanguage, targetLang)
+	return c.SendString(translated)
+}
@@ -0,0 +1,17 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/ncecere/reader-go/internal/core/ai"
+)
+
+// requestUsage returns the AI usage recorded for the request so far, or nil when
+// the route does not track usage
+func requestUsage(c *fiber.Ctx) *ai.Usage {
+	tracker := ai.UsageTrackerFrom(c.UserContext())
+	if tracker == nil {
+		return nil
+	}
+	usage := tracker.Usage()
+	return &usage
+}
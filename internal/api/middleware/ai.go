@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/ncecere/reader-go/internal/core/ai"
+)
+
+// AIProvider routes a request's AI calls to the provider named in the X-AI-Provider header.
+// Requests without the header use the default provider.
+func AIProvider(service *ai.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		name := strings.TrimSpace(c.Get("X-AI-Provider"))
+		if name == "" {
+			return c.Next()
+		}
+
+		if !service.HasProvider(name) {
+			return c.Status(400).SendString(fmt.Sprintf("Unknown AI provider %q (available: %s)",
+				name, strings.Join(service.Providers(), ", ")))
+		}
+
+		c.SetUserContext(ai.WithProvider(c.UserContext(), name))
+		return c.Next()
+	}
+}
+
+// AIUsage enforces the daily token budget of the caller's X-API-Key and tracks the tokens
+// used by the request. Usage is reported in X-AI-*-Tokens response headers; JSON handlers
+// also include it in the body. Content redacted before AI calls is counted in X-Redactions.
+// Streamed responses make their AI calls after the headers are sent, so they get no usage
+// headers; they report usage in a final event and release the budget reservation themselves.
+func AIUsage(service *ai.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// "/summary/*" -> "summary"
+		endpoint := strings.SplitN(strings.TrimPrefix(c.Route().Path, "/"), "/", 2)[0]
+		tracker := ai.NewUsageTracker(endpoint, c.Get("X-API-Key"))
+		if err := service.ReserveBudget(tracker); err != nil {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(secondsUntilUTCMidnight(time.Now())))
+			return c.Status(429).SendString(err.Error())
+		}
+		c.SetUserContext(ai.WithUsageTracker(c.UserContext(), tracker))
+
+		err := c.Next()
+		if c.Response().IsBodyStream() {
+			return err
+		}
+		service.ReleaseBudget(tracker)
+
+		usage := tracker.Usage()
+		c.Set("X-AI-Prompt-Tokens", strconv.Itoa(usage.PromptTokens))
+		c.Set("X-AI-Completion-Tokens", strconv.Itoa(usage.CompletionTokens))
+		c.Set("X-AI-Total-Tokens", strconv.Itoa(usage.TotalTokens))
+		if usage.EstimatedCost > 0 {
+			c.Set("X-AI-Estimated-Cost", strconv.FormatFloat(usage.EstimatedCost, 'f', 6, 64))
+		}
+		if redactions := tracker.Redactions(); redactions.Total() > 0 {
+			c.Set("X-Redactions", redactions.String())
+		}
+		return err
+	}
+}
+
+// secondsUntilUTCMidnight returns when daily budgets reset, for Retry-After
+func secondsUntilUTCMidnight(now time.Time) int {
+	now = now.UTC()
+	midnight := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+	return int(midnight.Sub(now).Seconds()) + 1
+}
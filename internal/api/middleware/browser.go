@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/ncecere/reader-go/internal/core/browser"
+)
+
+// AutoScroll makes the page fetches of requests with "X-Auto-Scroll: true" scroll
+// through the page first, so lazy-loaded and infinite-scroll content is included
+func AutoScroll() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if c.Get("X-Auto-Scroll") == "true" {
+			c.SetUserContext(browser.WithAutoScroll(c.UserContext()))
+		}
+		return c.Next()
+	}
+}
+
+// SetCookie sets the cookies of the "X-Set-Cookie: name=value; name2=value2" header
+// in the page fetches of the request. The cookies apply to the requested page's host.
+func SetCookie() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if header := c.Get("X-Set-Cookie"); header != "" {
+			if cookies := browser.ParseCookieHeader(header); len(cookies) > 0 {
+				session := &browser.Session{Cookies: cookies}
+				c.SetUserContext(browser.WithSession(c.UserContext(), session))
+			}
+		}
+		return c.Next()
+	}
+}
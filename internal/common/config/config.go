@@ -16,17 +16,87 @@ type Config struct {
 
 	AI struct {
 		Enabled     bool   `yaml:"enabled"`
+		Provider    string `yaml:"provider"`
 		APIEndpoint string `yaml:"api_endpoint"`
 		APIKey      string `yaml:"api_key"`
 		Model       string `yaml:"model"`
 		Prompt      string `yaml:"prompt"`
+
+		Prompts            map[string]string `yaml:"prompts"`
+		AllowCustomPrompts bool              `yaml:"allow_custom_prompts"`
+
+		EmbeddingModel string `yaml:"embedding_model"`
+		MaxInputTokens int    `yaml:"max_input_tokens"`
+		Concurrency    int    `yaml:"concurrency"`
+
+		JSONMode bool `yaml:"json_mode"`
+
+		// APIVersion is the api-version query parameter sent to Azure OpenAI
+		APIVersion string `yaml:"api_version"`
+		// Providers are additional backends that requests can select by name
+		Providers map[string]ProviderConfig `yaml:"providers"`
+
+		// Timeout bounds each upstream attempt until response headers arrive, in seconds
+		Timeout    int `yaml:"timeout"`
+		MaxRetries int `yaml:"max_retries"`
+		// BreakerThreshold consecutive failures open a provider's circuit for BreakerCooldown seconds
+		BreakerThreshold int `yaml:"breaker_threshold"`
+		BreakerCooldown  int `yaml:"breaker_cooldown"`
+		// Fallbacks are tried in order when the selected provider fails
+		Fallbacks []FallbackConfig `yaml:"fallbacks"`
+
+		// Prices maps model names to USD per million tokens, for cost estimates
+		Prices map[string]PriceConfig `yaml:"prices"`
+		// DailyTokenBudget caps the tokens callers not listed in KeyBudgets may use together per UTC day; 0 is unlimited
+		DailyTokenBudget int `yaml:"daily_token_budget"`
+		// KeyBudgets gives specific API keys a daily budget of their own
+		KeyBudgets map[string]int `yaml:"key_budgets"`
 	} `yaml:"ai"`
 
+	Redaction struct {
+		// Enabled masks sensitive data in page content before it is sent to AI providers
+		Enabled bool `yaml:"enabled"`
+		// Rules selects built-in rules by name; empty enables all of them
+		Rules []string `yaml:"rules"`
+		// Custom rules are applied after the built-in ones
+		Custom []RedactionRuleConfig `yaml:"custom"`
+	} `yaml:"redaction"`
+
+	// Proxy routes Chrome and the HTTP clients through proxies
+	Proxy ProxyConfig `yaml:"proxy"`
+
 	Browser struct {
 		PoolSize   int    `yaml:"pool_size"`
+		Processes  int    `yaml:"processes"`
 		ChromePath string `yaml:"chrome_path"`
 		Timeout    int    `yaml:"timeout"`
 		MaxRetries int    `yaml:"max_retries"`
+
+		// Processes are recycled after MaxUses tabs or MaxAge minutes and are health
+		// checked every HealthCheckInterval seconds
+		MaxUses             int `yaml:"max_uses"`
+		MaxAge              int `yaml:"max_age"`
+		HealthCheckInterval int `yaml:"health_check_interval"`
+		// MemoryLimit is the RSS in MB above which a process is recycled
+		MemoryLimit int `yaml:"memory_limit"`
+
+		// MaxQueue requests wait up to QueueTimeout seconds for a tab
+		MaxQueue     int `yaml:"max_queue"`
+		QueueTimeout int `yaml:"queue_timeout"`
+
+		// Scroll limits auto-scrolling requested with X-Auto-Scroll
+		Scroll struct {
+			MaxScrolls int `yaml:"max_scrolls"`
+			Timeout    int `yaml:"timeout"`
+		} `yaml:"scroll"`
+
+		// Blocking keeps Chrome from loading resources text extraction does not need
+		Blocking BlockingConfig `yaml:"blocking"`
+		// Consent dismisses cookie banners before page content is read
+		Consent ConsentConfig `yaml:"consent"`
+		// Sessions maps domains to the cookies, headers and basic auth their pages
+		// are fetched with
+		Sessions map[string]SessionConfig `yaml:"sessions"`
 	} `yaml:"browser"`
 
 	Screenshots struct {
@@ -42,6 +112,37 @@ type Config struct {
 	} `yaml:"logging"`
 }
 
+// ProviderConfig describes a named AI backend
+type ProviderConfig struct {
+	Type           string `yaml:"type" mapstructure:"type"`
+	APIEndpoint    string `yaml:"api_endpoint" mapstructure:"api_endpoint"`
+	APIKey         string `yaml:"api_key" mapstructure:"api_key"`
+	APIVersion     string `yaml:"api_version" mapstructure:"api_version"`
+	Model          string `yaml:"model" mapstructure:"model"`
+	EmbeddingModel string `yaml:"embedding_model" mapstructure:"embedding_model"`
+}
+
+// FallbackConfig names a provider and model to try when earlier ones fail.
+// An empty model uses the provider's configured model.
+type FallbackConfig struct {
+	Provider string `yaml:"provider" mapstructure:"provider"`
+	Model    string `yaml:"model" mapstructure:"model"`
+}
+
+// PriceConfig is the price of a model in USD per million tokens
+type PriceConfig struct {
+	Prompt     float64 `yaml:"prompt" mapstructure:"prompt"`
+	Completion float64 `yaml:"completion" mapstructure:"completion"`
+}
+
+// RedactionRuleConfig is a custom redaction rule. An empty replacement masks matches
+// with the upper-cased rule name in brackets.
+type RedactionRuleConfig struct {
+	Name        string `yaml:"name" mapstructure:"name"`
+	Pattern     string `yaml:"pattern" mapstructure:"pattern"`
+	Replacement string `yaml:"replacement" mapstructure:"replacement"`
+}
+
 // Load loads configuration from a YAML file
 func Load() (*Config, error) {
 	data, err := os.ReadFile("config.yml")
@@ -78,7 +179,10 @@ func Load() (*Config, error) {
 	}
 
 	// Set AI defaults
-	if config.AI.APIEndpoint == "" {
+	if config.AI.Provider == "" {
+		config.AI.Provider = "openai"
+	}
+	if config.AI.APIEndpoint == "" && config.AI.Provider == "openai" {
 		config.AI.APIEndpoint = "https://api.openai.com/v1"
 	}
 	if config.AI.Model == "" {
@@ -87,6 +191,108 @@ func Load() (*Config, error) {
 	if config.AI.Prompt == "" {
 		config.AI.Prompt = "Please summarize the following text:"
 	}
+	if config.AI.EmbeddingModel == "" {
+		config.AI.EmbeddingModel = "text-embedding-3-small"
+	}
+	if config.AI.MaxInputTokens == 0 {
+		config.AI.MaxInputTokens = 6000
+	}
+	if config.AI.Concurrency == 0 {
+		config.AI.Concurrency = 4
+	}
 
 	return &config, nil
 }
+
+// BlockingConfig selects the sub-resources pages load without. Resource types are CDP
+// names such as image, font or script; domains also block their subdomains.
+type BlockingConfig struct {
+	Enabled       bool     `yaml:"enabled" mapstructure:"enabled"`
+	ResourceTypes []string `yaml:"resource_types" mapstructure:"resource_types"`
+	Domains       []string `yaml:"domains" mapstructure:"domains"`
+	// HostsFile is a hosts-style blocklist such as those used for ad blocking
+	HostsFile string `yaml:"hosts_file" mapstructure:"hosts_file"`
+	// Sites overrides the rules for pages on a domain and its subdomains
+	Sites map[string]BlockSiteConfig `yaml:"sites" mapstructure:"sites"`
+}
+
+// BlockSiteConfig overrides the blocking rules for a site. ResourceTypes, when set,
+// replaces the default types; Domains are blocked in addition to the global list and
+// Allow exempts domains from it.
+type BlockSiteConfig struct {
+	Disabled      bool     `yaml:"disabled" mapstructure:"disabled"`
+	ResourceTypes []string `yaml:"resource_types" mapstructure:"resource_types"`
+	Domains       []string `yaml:"domains" mapstructure:"domains"`
+	Allow         []string `yaml:"allow" mapstructure:"allow"`
+}
+
+// ConsentConfig controls cookie banner dismissal. Action is accept (default) or
+// reject; Wait is how many milliseconds to wait for a dialog to appear (0 = default
+// 1000, -1 = no wait). Dialogs that stay open after the click are removed from the
+// page unless KeepOverlays is set.
+type ConsentConfig struct {
+	Enabled      bool                `yaml:"enabled" mapstructure:"enabled"`
+	Action       string              `yaml:"action" mapstructure:"action"`
+	Wait         int                 `yaml:"wait" mapstructure:"wait"`
+	KeepOverlays bool                `yaml:"keep_overlays" mapstructure:"keep_overlays"`
+	Rules        []ConsentRuleConfig `yaml:"rules" mapstructure:"rules"`
+}
+
+// ConsentRuleConfig describes a consent platform by CSS selectors: Detect matches the
+// dialog, Accept and Reject its buttons and Remove the elements to drop if clicking
+// does not close it (Detect by default)
+type ConsentRuleConfig struct {
+	Name   string `yaml:"name" mapstructure:"name"`
+	Detect string `yaml:"detect" mapstructure:"detect"`
+	Accept string `yaml:"accept" mapstructure:"accept"`
+	Reject string `yaml:"reject" mapstructure:"reject"`
+	Remove string `yaml:"remove" mapstructure:"remove"`
+}
+
+// SessionConfig is what pages on a domain and its subdomains are fetched with.
+// CookieFile is a Netscape cookie jar as exported by curl or browser extensions;
+// Headers are sent with every request the page makes, while BasicAuth is only
+// offered to the page's own host.
+type SessionConfig struct {
+	Cookies    []CookieConfig    `yaml:"cookies" mapstructure:"cookies"`
+	CookieFile string            `yaml:"cookie_file" mapstructure:"cookie_file"`
+	Headers    map[string]string `yaml:"headers" mapstructure:"headers"`
+	BasicAuth  *BasicAuthConfig  `yaml:"basic_auth" mapstructure:"basic_auth"`
+}
+
+// CookieConfig is a cookie set before the page loads. Domain defaults to the
+// configured domain and its subdomains.
+type CookieConfig struct {
+	Name     string `yaml:"name" mapstructure:"name"`
+	Value    string `yaml:"value" mapstructure:"value"`
+	Domain   string `yaml:"domain" mapstructure:"domain"`
+	Path     string `yaml:"path" mapstructure:"path"`
+	Secure   bool   `yaml:"secure" mapstructure:"secure"`
+	HTTPOnly bool   `yaml:"http_only" mapstructure:"http_only"`
+}
+
+// BasicAuthConfig holds HTTP basic auth credentials
+type BasicAuthConfig struct {
+	Username string `yaml:"username" mapstructure:"username"`
+	Password string `yaml:"password" mapstructure:"password"`
+}
+
+// ProxyConfig selects the proxies outgoing requests use. URLs are http://, https://,
+// socks5:// or socks5h:// with optional user:password@; several URLs form a pool that
+// is rotated through. A proxy that fails MaxFailures times in a row (0 = 3) is skipped
+// for Cooldown seconds (0 = 60). Bypass lists hosts reached directly, and Sites
+// overrides the proxies for a domain and its subdomains.
+type ProxyConfig struct {
+	URLs        []string                   `yaml:"urls" mapstructure:"urls"`
+	Bypass      []string                   `yaml:"bypass" mapstructure:"bypass"`
+	MaxFailures int                        `yaml:"max_failures" mapstructure:"max_failures"`
+	Cooldown    int                        `yaml:"cooldown" mapstructure:"cooldown"`
+	Sites       map[string]ProxySiteConfig `yaml:"sites" mapstructure:"sites"`
+}
+
+// ProxySiteConfig overrides the proxies for a site: URLs replaces the global pool, and
+// Direct connects without a proxy
+type ProxySiteConfig struct {
+	URLs   []string `yaml:"urls" mapstructure:"urls"`
+	Direct bool     `yaml:"direct" mapstructure:"direct"`
+}
@@ -62,4 +62,182 @@ var (
 		},
 		[]string{"domain"},
 	)
+
+	// AIRequestRetries tracks retried upstream AI requests by provider and status
+	AIRequestRetries = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "reader_ai_request_retries_total",
+			Help: "Retried upstream AI requests by provider and status",
+		},
+		[]string{"provider", "status"},
+	)
+
+	// AIFallbacks tracks requests served by a fallback after the preceding provider failed
+	AIFallbacks = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "reader_ai_fallbacks_total",
+			Help: "AI requests that fell back from one provider/model to the next",
+		},
+		[]string{"from", "to"},
+	)
+
+	// AICircuitState tracks each provider's circuit breaker (0 closed, 1 half-open, 2 open)
+	AICircuitState = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "reader_ai_circuit_state",
+			Help: "AI provider circuit breaker state (0 closed, 1 half-open, 2 open)",
+		},
+		[]string{"provider"},
+	)
+
+	// AICircuitOpens tracks how often each provider's circuit breaker has opened
+	AICircuitOpens = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "reader_ai_circuit_opens_total",
+			Help: "Times an AI provider circuit breaker opened",
+		},
+		[]string{"provider"},
+	)
+
+	// AITokens tracks tokens used by AI calls by model, API endpoint and type (prompt, completion)
+	AITokens = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "reader_ai_tokens_total",
+			Help: "Tokens used by AI calls by model, endpoint and type",
+		},
+		[]string{"model", "endpoint", "type"},
+	)
+
+	// AICost tracks estimated AI spend in USD by model and API endpoint
+	AICost = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "reader_ai_estimated_cost_usd_total",
+			Help: "Estimated AI spend in USD by model and endpoint",
+		},
+		[]string{"model", "endpoint"},
+	)
+
+	// Redactions tracks masked sensitive data by rule and target (ai, output)
+	Redactions = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "reader_redactions_total",
+			Help: "Sensitive data matches masked by redaction rule and target",
+		},
+		[]string{"rule", "target"},
+	)
+
+	// BrowserRecycles tracks browser processes replaced by reason (crashed, unhealthy, max_uses, max_age, memory)
+	BrowserRecycles = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "reader_browser_recycles_total",
+			Help: "Browser processes recycled by reason",
+		},
+		[]string{"reason"},
+	)
+
+	// BrowserTabs tracks open browser tabs, one per in-flight request
+	BrowserTabs = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "reader_browser_tabs_active",
+			Help: "Open browser tabs",
+		},
+	)
+
+	// BrowserProcesses tracks running Chrome processes in the pool
+	BrowserProcesses = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "reader_browser_processes",
+			Help: "Chrome processes in the browser pool",
+		},
+	)
+
+	// BrowserMemory tracks the RSS of each Chrome process including its renderers and other children
+	BrowserMemory = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "reader_browser_memory_bytes",
+			Help: "Resident memory of each Chrome process tree",
+		},
+		[]string{"process"},
+	)
+
+	// BrowserCPU tracks the CPU each Chrome process tree used since the previous sample, in cores
+	BrowserCPU = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "reader_browser_cpu_usage",
+			Help: "CPU used by each Chrome process tree in cores",
+		},
+		[]string{"process"},
+	)
+
+	// BrowserJSHeap tracks the JS heap used by pages when their request finishes
+	BrowserJSHeap = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "reader_browser_js_heap_bytes",
+			Help:    "JS heap used by a page at the end of its request",
+			Buckets: prometheus.ExponentialBuckets(1<<20, 2, 10), // 1MB to 512MB
+		},
+	)
+
+	// BrowserBlockedRequests tracks page sub-resources that were not loaded, by resource
+	// type and whether the type or the domain was blocked
+	BrowserBlockedRequests = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "reader_browser_blocked_requests_total",
+			Help: "Page requests blocked by resource type and reason",
+		},
+		[]string{"resource_type", "reason"},
+	)
+
+	// ProxyRequests tracks requests sent through each proxy by client (browser, ai) and
+	// result (success, failure)
+	ProxyRequests = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "reader_proxy_requests_total",
+			Help: "Requests sent through each proxy by client and result",
+		},
+		[]string{"client", "proxy", "result"},
+	)
+
+	// ProxyUp tracks whether each proxy is in rotation (1) or cooling down after failures (0)
+	ProxyUp = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "reader_proxy_up",
+			Help: "Whether a proxy is in rotation",
+		},
+		[]string{"client", "proxy"},
+	)
+
+	// ConsentDismissals tracks consent dialogs dismissed by platform and method (click, remove)
+	ConsentDismissals = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "reader_consent_dismissals_total",
+			Help: "Cookie consent dialogs dismissed by platform and method",
+		},
+		[]string{"platform", "method"},
+	)
+
+	// BrowserQueueDepth tracks requests waiting for a browser tab
+	BrowserQueueDepth = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "reader_browser_queue_depth",
+			Help: "Requests waiting for a browser tab",
+		},
+	)
+
+	// BrowserQueueWait tracks how long requests waited for a browser tab
+	BrowserQueueWait = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "reader_browser_queue_wait_seconds",
+			Help:    "Time requests waited for a browser tab",
+			Buckets: []float64{.01, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+		},
+	)
+
+	// BrowserQueueRejections tracks requests turned away because the queue was full or the wait timed out
+	BrowserQueueRejections = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "reader_browser_queue_rejections_total",
+			Help: "Requests rejected because no browser tab became available",
+		},
+	)
 )
@@ -0,0 +1,135 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ncecere/reader-go/internal/core/chunker"
+	"github.com/ncecere/reader-go/internal/core/search"
+)
+
+// askPassageTokens is the size of the passages that are ranked against a question
+const askPassageTokens = 200
+
+// askPrompt instructs the model to answer only from the supplied passages
+const askPrompt = "You answer questions about a web page using only the numbered context passages provided. " +
+	"If the passages do not contain the answer, say that the page does not say. Do not use outside knowledge. " +
+	"Respond with JSON only, in the form " +
+	`{"answer": "<concise answer>", "quotes": ["<exact sentence copied from the passages>", ...]}` +
+	". Quotes must be copied verbatim from the passages and support the answer."
+
+// Answer is a grounded answer to a question about a page
+type Answer struct {
+	Answer string   `json:"answer"`
+	Quotes []string `json:"quotes"`
+}
+
+// Ask answers a question about text. Long texts are split into passages and only the
+// passages most relevant to the question (by BM25) are sent to the model.
+// Quotes that do not appear in the text are discarded.
+func (s *Service) Ask(ctx context.Context, question, text string) (*Answer, error) {
+	if !s.config.AI.Enabled {
+		return nil, fmt.Errorf("AI features are not enabled")
+	}
+
+	passages, err := s.selectPassages(question, text)
+	if err != nil {
+		return nil, err
+	}
+
+	var user strings.Builder
+	user.WriteString("Context passages:\n\n")
+	for i, p := range passages {
+		fmt.Fprintf(&user, "[%d] %s\n\n", i+1, strings.TrimSpace(p))
+	}
+	user.WriteString("Question: " + question)
+
+	raw, err := s.complete(ctx, askPrompt, user.String())
+	if err != nil {
+		return nil, err
+	}
+
+	answer := parseAnswer(raw)
+	// Quotes copied from redacted passages contain the redaction placeholders
+	answer.Quotes = verifyQuotes(answer.Quotes, s.redacted(text))
+	return answer, nil
+}
+
+// selectPassages returns the passages that match the question and fit the token budget,
+// most relevant first, then restores page order so the model reads them in context.
+// Passages are no larger than the budget, and the best one is always sent.
+func (s *Service) selectPassages(question, text string) ([]string, error) {
+	budget := s.maxInputTokens() * 3 / 4
+	if chunker.EstimateTokens(text) <= budget {
+		return []string{text}, nil
+	}
+
+	size := min(askPassageTokens, budget)
+	chunks, err := chunker.Split(text, "", &chunker.Options{
+		Size:    size,
+		Overlap: size / 10,
+		Unit:    chunker.Tokens,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to chunk text: %w", err)
+	}
+
+	docs := make([]string, len(chunks))
+	for i, c := range chunks {
+		docs[i] = c.Text
+	}
+
+	ranked := search.Rank(question, docs)
+	var selected []int
+	used := 0
+	for _, r := range ranked {
+		if r.Score <= 0 {
+			// The rest share no terms with the question
+			break
+		}
+		if used+chunks[r.Index].Tokens > budget {
+			continue
+		}
+		selected = append(selected, r.Index)
+		used += chunks[r.Index].Tokens
+	}
+	if len(selected) == 0 && len(ranked) > 0 {
+		selected = append(selected, ranked[0].Index)
+	}
+	sort.Ints(selected)
+
+	passages := make([]string, len(selected))
+	for i, idx := range selected {
+		passages[i] = docs[idx]
+	}
+	return passages, nil
+}
+
+// parseAnswer decodes the model's JSON reply, tolerating code fences and surrounding prose.
+// Replies that are not JSON are returned as the answer with no quotes.
+func parseAnswer(raw string) *Answer {
+	start, end := strings.Index(raw, "{"), strings.LastIndex(raw, "}")
+	if start >= 0 && end > start {
+		var answer Answer
+		if err := json.Unmarshal([]byte(raw[start:end+1]), &answer); err == nil && answer.Answer != "" {
+			return &answer
+		}
+	}
+	return &Answer{Answer: strings.TrimSpace(raw)}
+}
+
+// verifyQuotes keeps only quotes that occur in text, ignoring whitespace differences
+func verifyQuotes(quotes []string, text string) []string {
+	normalized := strings.Join(strings.Fields(text), " ")
+	verified := make([]string, 0, len(quotes))
+	for _, q := range quotes {
+		q = strings.Join(strings.Fields(q), " ")
+		if q != "" && strings.Contains(normalized, q) {
+			verified = append(verified, q)
+		}
+	}
+	return verified
+}
@@ -0,0 +1,86 @@
+package ai_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ncecere/reader-go/internal/core/ai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAsk(t *testing.T) {
+	var userMessage string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ai.ChatRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		userMessage = req.Messages[1].Content
+		writeChatReply(w, "```json\n"+`{"answer": "$20 per month.", "quotes": [`+
+			`"The Pro plan  costs $20 per month.", "An invented quote."]}`+"\n```")
+	}))
+	defer ts.Close()
+
+	cfg := newTestConfig(ts.URL)
+	cfg.AI.MaxInputTokens = 400
+	svc := ai.NewService(cfg)
+
+	filler := strings.Repeat("Our team loves building tools for developers around the world.\n\n", 60)
+	text := filler + "Pricing\n\nThe Pro plan costs $20 per month.\n\n" + filler
+
+	answer, err := svc.Ask(context.Background(), "How much is the Pro plan?", text)
+	require.NoError(t, err)
+
+	assert.Equal(t, "$20 per month.", answer.Answer)
+	assert.Equal(t, []string{"The Pro plan costs $20 per month."}, answer.Quotes)
+	assert.Contains(t, userMessage, "The Pro plan costs $20 per month.")
+	assert.Contains(t, userMessage, "Question: How much is the Pro plan?")
+	assert.Less(t, len(userMessage), len(text)/2, "only relevant passages should be sent")
+}
+
+func TestAskPlainReply(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeChatReply(w, "The page does not say.")
+	}))
+	defer ts.Close()
+
+	answer, err := ai.NewService(newTestConfig(ts.URL)).Ask(context.Background(), "Who?", "Some text.")
+	require.NoError(t, err)
+	assert.Equal(t, "The page does not say.", answer.Answer)
+	assert.Empty(t, answer.Quotes)
+}
+
+func TestAskPassageSelection(t *testing.T) {
+	var userMessage string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ai.ChatRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		userMessage = req.Messages[1].Content
+		writeChatReply(w, `{"answer": "$20 per month.", "quotes": []}`)
+	}))
+	defer ts.Close()
+
+	filler := strings.Repeat("Our team loves building tools for developers around the world.\n\n", 60)
+	text := filler + "Pricing\n\nThe Pro plan costs $20 per month.\n\n" + filler
+
+	cfg := newTestConfig(ts.URL)
+	cfg.AI.MaxInputTokens = 2000
+	_, err := ai.NewService(cfg).Ask(context.Background(), "How much is the Pro plan?", text)
+	require.NoError(t, err)
+	assert.Contains(t, userMessage, "The Pro plan costs $20 per month.")
+	assert.NotContains(t, userMessage, "[2]", "passages unrelated to the question are not sent")
+
+	// A budget below the default passage size still gets the relevant passage
+	cfg.AI.MaxInputTokens = 80
+	_, err = ai.NewService(cfg).Ask(context.Background(), "How much is the Pro plan?", text)
+	require.NoError(t, err)
+	assert.Contains(t, userMessage, "The Pro plan costs $20 per month.")
+
+	// Without any matching passage the best ranked one is sent rather than nothing
+	_, err = ai.NewService(cfg).Ask(context.Background(), "Xylophone?", text)
+	require.NoError(t, err)
+	assert.Contains(t, userMessage, "[1] Our team loves building tools")
+}
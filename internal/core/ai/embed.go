@@ -0,0 +1,98 @@
+package ai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// embeddingBatchSize caps how many inputs are sent in a single /embeddings request
+const embeddingBatchSize = 64
+
+// EmbeddingModel returns the embedding model of the provider selected for ctx
+func (s *Service) EmbeddingModel(ctx context.Context) string {
+	b, err := s.backend(ctx)
+	if err != nil {
+		return ""
+	}
+	return b.embeddingModel
+}
+
+// Embed returns one vector per input using the configured embedding model.
+// Vectors are cached by a hash of the model and input text, so only new content is sent upstream.
+func (s *Service) Embed(ctx context.Context, inputs []string) ([][]float64, error) {
+	if !s.config.AI.Enabled {
+		return nil, fmt.Errorf("AI features are not enabled")
+	}
+
+	b, err := s.backend(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	vectors := make([][]float64, len(inputs))
+	var missing []int
+	for i, input := range inputs {
+		if cached, found := s.embeddings.Get(s.embeddingKey(b, input)); found {
+			if err := json.Unmarshal([]byte(cached), &vectors[i]); err == nil {
+				continue
+			}
+		}
+		missing = append(missing, i)
+	}
+
+	for start := 0; start < len(missing); start += embeddingBatchSize {
+		end := start + embeddingBatchSize
+		if end > len(missing) {
+			end = len(missing)
+		}
+		if err := s.embedBatch(ctx, b, inputs, missing[start:end], vectors); err != nil {
+			return nil, err
+		}
+	}
+
+	return vectors, nil
+}
+
+// embedBatch requests embeddings for the inputs at the given indexes and caches the results
+func (s *Service) embedBatch(ctx context.Context, b *backend, inputs []string, indexes []int, vectors [][]float64) error {
+	batch := make([]string, len(indexes))
+	for i, idx := range indexes {
+		batch[i] = s.redact(ctx, inputs[idx])
+	}
+
+	// Vectors from different models are not comparable, so embeddings never fall back
+	var results [][]float64
+	err := s.withFallback(ctx, false, func(t target) (bool, error) {
+		embeddings, err := t.backend.provider.Embed(ctx, t.backend.embeddingModel, batch)
+		if err != nil {
+			return false, err
+		}
+		s.recordUsage(ctx, t.backend.embeddingModel, embeddings.Usage, strings.Join(batch, ""), "")
+		results = embeddings.Vectors
+		return false, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for i, vector := range results {
+		idx := indexes[i]
+		vectors[idx] = vector
+
+		if data, err := json.Marshal(vector); err == nil {
+			s.embeddings.Set(s.embeddingKey(b, inputs[idx]), string(data))
+		}
+	}
+
+	return nil
+}
+
+// embeddingKey hashes the backend, embedding model and input so vectors from different models never mix
+func (s *Service) embeddingKey(b *backend, input string) string {
+	hash := sha256.Sum256([]byte(b.name + "\x00" + b.embeddingModel + "\x00" + input))
+	return hex.EncodeToString(hash[:])
+}
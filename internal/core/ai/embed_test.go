@@ -0,0 +1,54 @@
+package ai_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ncecere/reader-go/internal/core/ai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmbedCachesByContent(t *testing.T) {
+	var calls, inputs int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		assert.Equal(t, "/embeddings", r.URL.Path)
+		assert.Equal(t, "Bearer test-key", r.Header.Get("Authorization"))
+
+		var req ai.EmbeddingRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "embed-model", req.Model)
+		atomic.AddInt32(&inputs, int32(len(req.Input)))
+
+		type item struct {
+			Index     int       `json:"index"`
+			Embedding []float64 `json:"embedding"`
+		}
+		data := make([]item, len(req.Input))
+		for i, in := range req.Input {
+			data[i] = item{Index: i, Embedding: []float64{float64(len(in))}}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+	}))
+	defer ts.Close()
+
+	cfg := newTestConfig(ts.URL)
+	cfg.AI.EmbeddingModel = "embed-model"
+	svc := ai.NewService(cfg)
+
+	vectors, err := svc.Embed(context.Background(), []string{"a", "bb"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float64{{1}, {2}}, vectors)
+
+	vectors, err = svc.Embed(context.Background(), []string{"bb", "ccc"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float64{{2}, {3}}, vectors)
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&inputs), "cached input should not be resent")
+}
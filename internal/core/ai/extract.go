@@ -0,0 +1,139 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ncecere/reader-go/internal/core/chunker"
+	"github.com/ncecere/reader-go/internal/core/schema"
+)
+
+// extractPrompt instructs the model to fill a JSON Schema from the page text
+const extractPrompt = "You extract structured data from web pages. " +
+	"Read the page content and return a single JSON value that conforms to the JSON Schema below. " +
+	"Use only information found in the page. When a value is not present, use null if the schema allows it, " +
+	"otherwise omit optional properties. Respond with JSON only, without code fences or commentary.\n\nJSON Schema:\n"
+
+// ErrInvalidSchema is returned when the caller's JSON Schema cannot be used
+var ErrInvalidSchema = errors.New("invalid JSON schema")
+
+// ExtractionError is returned when the model's output still fails validation after a repair attempt
+type ExtractionError struct {
+	Data   json.RawMessage
+	Errors []schema.ValidationError
+}
+
+func (e *ExtractionError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, ve := range e.Errors {
+		msgs[i] = ve.Error()
+	}
+	return "extracted data does not match schema: " + strings.Join(msgs, "; ")
+}
+
+// Extract asks the model for data matching the JSON Schema in rawSchema and validates the
+// reply. If validation fails, the errors are sent back to the model once for repair.
+// Texts longer than the token budget are truncated; the leading content of a page is
+// usually where the extractable facts live.
+func (s *Service) Extract(ctx context.Context, text string, rawSchema json.RawMessage) (json.RawMessage, error) {
+	if !s.config.AI.Enabled {
+		return nil, fmt.Errorf("AI features are not enabled")
+	}
+
+	sch, err := schema.Parse(rawSchema)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+	}
+
+	req := ChatRequest{
+		Messages: []Message{
+			{Role: "system", Content: extractPrompt + string(rawSchema)},
+			{Role: "user", Content: truncateTokens(text, s.maxInputTokens())},
+		},
+	}
+	if s.config.AI.JSONMode {
+		req.ResponseFormat = &ResponseFormat{Type: "json_object"}
+	}
+
+	raw, err := s.chat(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	data, problems := validateExtraction(sch, raw)
+	if len(problems) == 0 {
+		return data, nil
+	}
+
+	// One repair round trip: show the model its reply and what was wrong with it
+	req.Messages = append(req.Messages,
+		Message{Role: "assistant", Content: raw},
+		Message{Role: "user", Content: repairMessage(problems)},
+	)
+	raw, err = s.chat(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	data, problems = validateExtraction(sch, raw)
+	if len(problems) > 0 {
+		return nil, &ExtractionError{Data: data, Errors: problems}
+	}
+	return data, nil
+}
+
+// validateExtraction pulls the JSON value out of a model reply and validates it.
+// Replies that are not JSON are reported as a single validation error.
+func validateExtraction(sch *schema.Schema, raw string) (json.RawMessage, []schema.ValidationError) {
+	data := json.RawMessage(stripJSON(raw))
+	problems, err := sch.Validate(data)
+	if err != nil {
+		return nil, []schema.ValidationError{{Path: "$", Message: err.Error()}}
+	}
+	return data, problems
+}
+
+// stripJSON removes code fences and prose around the first JSON object or array in raw
+func stripJSON(raw string) string {
+	raw = strings.TrimSpace(raw)
+	start := strings.IndexAny(raw, "{[")
+	if start < 0 {
+		return raw
+	}
+	closing := "}"
+	if raw[start] == '[' {
+		closing = "]"
+	}
+	end := strings.LastIndex(raw, closing)
+	if end < start {
+		return raw
+	}
+	return raw[start : end+1]
+}
+
+// repairMessage lists validation errors for the model to correct
+func repairMessage(problems []schema.ValidationError) string {
+	var b strings.Builder
+	b.WriteString("Your JSON does not conform to the schema:\n")
+	for _, p := range problems {
+		b.WriteString("- " + p.Error() + "\n")
+	}
+	b.WriteString("Return the corrected JSON only.")
+	return b.String()
+}
+
+// truncateTokens cuts text to roughly maxTokens at a rune boundary
+func truncateTokens(text string, maxTokens int) string {
+	limit := maxTokens * chunker.CharsPerToken
+	if len(text) <= limit {
+		return text
+	}
+	for limit > 0 && !utf8.RuneStart(text[limit]) {
+		limit--
+	}
+	return text[:limit]
+}
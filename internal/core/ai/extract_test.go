@@ -0,0 +1,81 @@
+package ai_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ncecere/reader-go/internal/core/ai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const priceSchema = `{"type": "object", "required": ["price"], "properties": {"price": {"type": "number"}}}`
+
+func TestExtract(t *testing.T) {
+	var req ai.ChatRequest
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		writeChatReply(w, "```json\n{\"price\": 20}\n```")
+	}))
+	defer ts.Close()
+
+	cfg := newTestConfig(ts.URL)
+	cfg.AI.JSONMode = true
+	data, err := ai.NewService(cfg).Extract(context.Background(), "The Pro plan costs $20.", json.RawMessage(priceSchema))
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"price": 20}`, string(data))
+	assert.Contains(t, req.Messages[0].Content, priceSchema)
+	assert.Equal(t, "The Pro plan costs $20.", req.Messages[1].Content)
+	require.NotNil(t, req.ResponseFormat)
+	assert.Equal(t, "json_object", req.ResponseFormat.Type)
+}
+
+func TestExtractRepairsOnce(t *testing.T) {
+	replies := []string{`{"price": "twenty"}`, `{"price": 20}`}
+	var requests []ai.ChatRequest
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ai.ChatRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requests = append(requests, req)
+		writeChatReply(w, replies[len(requests)-1])
+	}))
+	defer ts.Close()
+
+	data, err := ai.NewService(newTestConfig(ts.URL)).Extract(context.Background(), "text", json.RawMessage(priceSchema))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"price": 20}`, string(data))
+
+	require.Len(t, requests, 2)
+	assert.Nil(t, requests[0].ResponseFormat)
+	repair := requests[1].Messages
+	require.Len(t, repair, 4)
+	assert.Equal(t, `{"price": "twenty"}`, repair[2].Content)
+	assert.Contains(t, repair[3].Content, "$.price: expected [number], got string")
+}
+
+func TestExtractFailsAfterRepair(t *testing.T) {
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		writeChatReply(w, `{}`)
+	}))
+	defer ts.Close()
+
+	_, err := ai.NewService(newTestConfig(ts.URL)).Extract(context.Background(), "text", json.RawMessage(priceSchema))
+
+	var extractionErr *ai.ExtractionError
+	require.True(t, errors.As(err, &extractionErr))
+	assert.JSONEq(t, `{}`, string(extractionErr.Data))
+	assert.Len(t, extractionErr.Errors, 1)
+	assert.Equal(t, 2, calls)
+}
+
+func TestExtractInvalidSchema(t *testing.T) {
+	_, err := ai.NewService(newTestConfig("http://unused")).Extract(context.Background(), "text", json.RawMessage(`{"type": "text"}`))
+	assert.True(t, errors.Is(err, ai.ErrInvalidSchema))
+}
@@ -0,0 +1,82 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ncecere/reader-go/internal/common/logger"
+	"github.com/ncecere/reader-go/internal/common/metrics"
+	"go.uber.org/zap"
+)
+
+// target is one provider/model pair in a request's fallback chain
+type target struct {
+	backend *backend
+	model   string
+}
+
+func (t target) String() string {
+	return t.backend.name + "/" + t.model
+}
+
+// targets returns the provider selected for ctx followed by the configured fallbacks,
+// without duplicates
+func (s *Service) targets(ctx context.Context) ([]target, error) {
+	b, err := s.backend(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	chain := []target{{backend: b, model: b.model}}
+	seen := map[string]bool{chain[0].String(): true}
+	for _, fb := range s.config.AI.Fallbacks {
+		fbBackend := s.backends[fb.Provider]
+		model := fb.Model
+		if model == "" {
+			model = fbBackend.model
+		}
+		t := target{backend: fbBackend, model: model}
+		if !seen[t.String()] {
+			seen[t.String()] = true
+			chain = append(chain, t)
+		}
+	}
+	return chain, nil
+}
+
+// withFallback runs call against each target in turn until one succeeds. Providers whose
+// circuit is open are skipped. Only the first target is used when fallback is false, or
+// once call reports that it has already produced output.
+func (s *Service) withFallback(ctx context.Context, fallback bool, call func(target) (committed bool, err error)) error {
+	chain, err := s.targets(ctx)
+	if err != nil {
+		return err
+	}
+	if !fallback {
+		chain = chain[:1]
+	}
+
+	var lastErr error
+	for i, t := range chain {
+		if i > 0 {
+			metrics.AIFallbacks.WithLabelValues(chain[i-1].String(), t.String()).Inc()
+			logger.Log.Warn("Falling back to next AI provider",
+				zap.String("from", chain[i-1].String()),
+				zap.String("to", t.String()),
+				zap.Error(lastErr))
+		}
+
+		if !t.backend.breaker.allow() {
+			lastErr = fmt.Errorf("%s: %w", t.backend.name, ErrCircuitOpen)
+			continue
+		}
+
+		committed, err := call(t)
+		t.backend.breaker.record(err)
+		if err == nil || committed || ctx.Err() != nil {
+			return err
+		}
+		lastErr = err
+	}
+	return lastErr
+}
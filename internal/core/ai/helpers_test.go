@@ -0,0 +1,35 @@
+package ai_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/ncecere/reader-go/internal/common/config"
+	"github.com/ncecere/reader-go/internal/common/logger"
+	"go.uber.org/zap"
+)
+
+func TestMain(m *testing.M) {
+	logger.Log = zap.NewNop()
+	os.Exit(m.Run())
+}
+
+// newTestConfig returns an enabled AI config pointing at a test server
+func newTestConfig(endpoint string) *config.Config {
+	cfg := &config.Config{}
+	cfg.AI.Enabled = true
+	cfg.AI.APIEndpoint = endpoint
+	cfg.AI.APIKey = "test-key"
+	cfg.AI.Model = "test-model"
+	cfg.AI.Prompt = "Summarize:"
+	return cfg
+}
+
+// writeChatReply writes an OpenAI-style chat completion response
+func writeChatReply(w http.ResponseWriter, content string) {
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"choices": []map[string]interface{}{{"message": map[string]string{"content": content}}},
+	})
+}
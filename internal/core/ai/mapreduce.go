@@ -0,0 +1,123 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/ncecere/reader-go/internal/common/logger"
+	"github.com/ncecere/reader-go/internal/core/chunker"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultMaxInputTokens is used when ai.max_input_tokens is not configured
+	defaultMaxInputTokens = 6000
+	// defaultConcurrency is used when ai.concurrency is not configured
+	defaultConcurrency = 4
+	// maxReduceRounds bounds how often partial summaries are re-summarized
+	maxReduceRounds = 4
+)
+
+// mapPrompt is the system prompt used to summarize a single chunk of a long document
+const mapPrompt = "You are summarizing one section of a longer document. " +
+	"Write a concise summary of this section only, keeping key facts, names, numbers and conclusions. " +
+	"Do not add an introduction or mention that this is a section."
+
+// condense is the map step of map-reduce summarization: while text exceeds the token
+// budget it is chunked and each chunk summarized concurrently. The result is small
+// enough for the final (reduce) completion with the configured prompt.
+func (s *Service) condense(ctx context.Context, text string) (string, error) {
+	for round := 1; chunker.EstimateTokens(text) > s.maxInputTokens(); round++ {
+		if round > maxReduceRounds {
+			return "", fmt.Errorf("document still exceeds %d tokens after %d reduce rounds",
+				s.maxInputTokens(), maxReduceRounds)
+		}
+
+		chunks, err := chunker.Split(text, "", &chunker.Options{
+			Size: s.maxInputTokens(),
+			Unit: chunker.Tokens,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to chunk text: %w", err)
+		}
+
+		logger.Log.Info("Summarizing long document in chunks",
+			zap.Int("round", round),
+			zap.Int("chunks", len(chunks)),
+			zap.Int("estimated_tokens", chunker.EstimateTokens(text)))
+
+		partials, err := s.mapChunks(ctx, mapPrompt, chunks)
+		if err != nil {
+			return "", err
+		}
+		text = strings.Join(partials, "\n\n")
+	}
+
+	return text, nil
+}
+
+// mapChunks completes each chunk with the given system prompt, with at most
+// s.concurrency() requests in flight. The first error cancels the remaining work.
+func (s *Service) mapChunks(ctx context.Context, prompt string, chunks []chunker.Chunk) ([]string, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]string, len(chunks))
+	sem := make(chan struct{}, s.concurrency())
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+
+	for i, chunk := range chunks {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(i int, text string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			result, err := s.complete(ctx, prompt, text)
+			if err != nil {
+				errOnce.Do(func() {
+					firstErr = fmt.Errorf("failed to process chunk %d of %d: %w", i+1, len(chunks), err)
+					cancel()
+				})
+				return
+			}
+			results[i] = result
+		}(i, chunk.Text)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (s *Service) maxInputTokens() int {
+	if s.config.AI.MaxInputTokens > 0 {
+		return s.config.AI.MaxInputTokens
+	}
+	return defaultMaxInputTokens
+}
+
+func (s *Service) concurrency() int {
+	if s.config.AI.Concurrency > 0 {
+		return s.config.AI.Concurrency
+	}
+	return defaultConcurrency
+}
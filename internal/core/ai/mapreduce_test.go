@@ -0,0 +1,75 @@
+package ai_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ncecere/reader-go/internal/core/ai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarizeMapReduce(t *testing.T) {
+	var mapCalls, reduceCalls, inFlight, maxInFlight int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		var req ai.ChatRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		reply := "partial"
+		if req.Messages[0].Content == "final prompt" {
+			atomic.AddInt32(&reduceCalls, 1)
+			reply = "final summary"
+		} else {
+			atomic.AddInt32(&mapCalls, 1)
+		}
+		writeChatReply(w, reply)
+	}))
+	defer ts.Close()
+
+	cfg := newTestConfig(ts.URL)
+	cfg.AI.Prompt = "final prompt"
+	cfg.AI.MaxInputTokens = 50
+	cfg.AI.Concurrency = 2
+	svc := ai.NewService(cfg)
+
+	text := strings.Repeat("This sentence is filler text for a long page.\n\n", 40)
+	summary, err := svc.Summarize(context.Background(), text, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "final summary", summary)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&reduceCalls))
+	assert.Greater(t, atomic.LoadInt32(&mapCalls), int32(5))
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxInFlight), int32(2))
+}
+
+func TestSummarizeShortTextSingleCall(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		writeChatReply(w, "short")
+	}))
+	defer ts.Close()
+
+	svc := ai.NewService(newTestConfig(ts.URL))
+
+	summary, err := svc.Summarize(context.Background(), "A short page.", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "short", summary)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
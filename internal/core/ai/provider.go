@@ -0,0 +1,248 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ncecere/reader-go/internal/common/config"
+	"github.com/ncecere/reader-go/internal/core/proxy"
+)
+
+// DefaultProvider is the name of the backend configured by the top-level ai settings
+const DefaultProvider = "default"
+
+var (
+	// ErrUnknownProvider is returned when a request selects a provider that is not configured
+	ErrUnknownProvider = errors.New("unknown AI provider")
+	// ErrNotSupported is returned when a provider does not offer an operation, e.g. embeddings
+	ErrNotSupported = errors.New("operation not supported by AI provider")
+)
+
+// Provider is an LLM backend. Requests use the OpenAI chat shape internally;
+// each provider translates them to its own API.
+type Provider interface {
+	// Chat returns a single chat completion
+	Chat(ctx context.Context, req *ChatRequest) (*Completion, error)
+	// ChatStream relays the completion to onDelta as it is generated and returns the full text
+	ChatStream(ctx context.Context, req *ChatRequest, onDelta DeltaFunc) (*Completion, error)
+	// Embed returns one vector per input
+	Embed(ctx context.Context, model string, inputs []string) (*Embeddings, error)
+}
+
+// Completion is the generated text of a chat request and the tokens it used.
+// Usage is zero when the provider does not report it.
+type Completion struct {
+	Content string
+	Usage   Usage
+}
+
+// Embeddings are the vectors for an embeddings request and the tokens it used
+type Embeddings struct {
+	Vectors [][]float64
+	Usage   Usage
+}
+
+// NewProvider creates the provider described by pc
+func NewProvider(pc config.ProviderConfig, client *http.Client) (Provider, error) {
+	switch strings.ToLower(pc.Type) {
+	case "", "openai":
+		return &openAIProvider{endpoint: strings.TrimSuffix(pc.APIEndpoint, "/"), apiKey: pc.APIKey, client: client}, nil
+	case "anthropic":
+		return newAnthropicProvider(pc, client), nil
+	case "azure":
+		return newAzureProvider(pc, client)
+	case "ollama":
+		return newOllamaProvider(pc, client), nil
+	default:
+		return nil, fmt.Errorf("unknown AI provider type %q", pc.Type)
+	}
+}
+
+// backend is a configured provider together with the models it serves
+type backend struct {
+	name           string
+	provider       Provider
+	model          string
+	embeddingModel string
+	breaker        *breaker
+}
+
+// cacheID identifies the backend and model in cache keys
+func (b *backend) cacheID() string {
+	return b.name + "\x00" + b.model
+}
+
+type providerKey struct{}
+
+// WithProvider returns a context that routes AI calls to the named provider
+func WithProvider(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, providerKey{}, name)
+}
+
+// providerFromContext returns the provider selected for ctx, or DefaultProvider
+func providerFromContext(ctx context.Context) string {
+	if name, ok := ctx.Value(providerKey{}).(string); ok && name != "" {
+		return name
+	}
+	return DefaultProvider
+}
+
+// newBackends builds the default backend from the top-level ai settings plus one per
+// ai.providers entry. Each backend gets its own retrying HTTP client and circuit breaker.
+func newBackends(cfg *config.Config) (map[string]*backend, error) {
+	configs := map[string]config.ProviderConfig{
+		DefaultProvider: {
+			Type:           cfg.AI.Provider,
+			APIEndpoint:    cfg.AI.APIEndpoint,
+			APIKey:         cfg.AI.APIKey,
+			APIVersion:     cfg.AI.APIVersion,
+			Model:          cfg.AI.Model,
+			EmbeddingModel: cfg.AI.EmbeddingModel,
+		},
+	}
+	for name, pc := range cfg.AI.Providers {
+		if name == DefaultProvider {
+			return nil, fmt.Errorf("provider name %q is reserved", DefaultProvider)
+		}
+		configs[name] = pc
+	}
+
+	timeout := durationOr(cfg.AI.Timeout, defaultTimeout)
+	maxRetries := cfg.AI.MaxRetries
+	if maxRetries == 0 {
+		maxRetries = defaultMaxRetries
+	} else if maxRetries < 0 {
+		maxRetries = 0
+	}
+	threshold := cfg.AI.BreakerThreshold
+	if threshold <= 0 {
+		threshold = defaultBreakerThreshold
+	}
+	cooldown := durationOr(cfg.AI.BreakerCooldown, defaultBreakerCooldown)
+	proxies, err := proxy.New(cfg.Proxy, "ai")
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy configuration: %w", err)
+	}
+
+	backends := make(map[string]*backend, len(configs))
+	for name, pc := range configs {
+		provider, err := NewProvider(pc, newHTTPClient(name, timeout, maxRetries, proxies))
+		if err != nil {
+			return nil, fmt.Errorf("provider %q: %w", name, err)
+		}
+		backends[name] = &backend{
+			name:           name,
+			provider:       provider,
+			model:          pc.Model,
+			embeddingModel: pc.EmbeddingModel,
+			breaker:        newBreaker(name, threshold, cooldown),
+		}
+	}
+
+	for _, fb := range cfg.AI.Fallbacks {
+		if _, ok := backends[fb.Provider]; !ok {
+			return nil, fmt.Errorf("fallback references unknown provider %q", fb.Provider)
+		}
+	}
+	return backends, nil
+}
+
+// durationOr converts seconds to a duration, using def when seconds is not positive
+func durationOr(seconds int, def time.Duration) time.Duration {
+	if seconds <= 0 {
+		return def
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// Providers returns the names of the configured providers, sorted
+func (s *Service) Providers() []string {
+	names := make([]string, 0, len(s.backends))
+	for name := range s.backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// HasProvider reports whether name is a configured provider
+func (s *Service) HasProvider(name string) bool {
+	_, ok := s.backends[name]
+	return ok
+}
+
+// backend returns the backend selected for ctx
+func (s *Service) backend(ctx context.Context) (*backend, error) {
+	if s.backendErr != nil {
+		return nil, s.backendErr
+	}
+	name := providerFromContext(ctx)
+	b, ok := s.backends[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownProvider, name)
+	}
+	return b, nil
+}
+
+// postJSON sends a JSON request and decodes the response into out
+func postJSON(ctx context.Context, client *http.Client, url string, header http.Header, reqBody interface{}, out interface{}) error {
+	resp, err := post(ctx, client, url, header, reqBody)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %v", err)
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	return nil
+}
+
+// post sends a JSON request with the given headers. The caller must close the
+// response body; non-200 responses are returned as errors.
+func post(ctx context.Context, client *http.Client, url string, header http.Header, reqBody interface{}) (*http.Response, error) {
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for key, values := range header {
+		for _, v := range values {
+			req.Header.Add(key, v)
+		}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		// Read response body for error cases
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,166 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ncecere/reader-go/internal/common/config"
+)
+
+const (
+	// defaultAnthropicEndpoint is used when api_endpoint is not configured
+	defaultAnthropicEndpoint = "https://api.anthropic.com/v1"
+	// anthropicVersion is the Messages API version sent with every request
+	anthropicVersion = "2023-06-01"
+	// anthropicMaxTokens caps the length of each reply; the Messages API requires a limit
+	anthropicMaxTokens = 4096
+)
+
+// anthropicProvider talks to the Anthropic Messages API
+type anthropicProvider struct {
+	endpoint string
+	apiKey   string
+	client   *http.Client
+}
+
+// anthropicRequest represents a Messages API request
+type anthropicRequest struct {
+	Model     string    `json:"model"`
+	System    string    `json:"system,omitempty"`
+	Messages  []Message `json:"messages"`
+	MaxTokens int       `json:"max_tokens"`
+	Stream    bool      `json:"stream,omitempty"`
+}
+
+// anthropicUsage is the token usage reported by the Messages API
+type anthropicUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+// anthropicResponse represents a Messages API response
+type anthropicResponse struct {
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	Usage anthropicUsage `json:"usage"`
+}
+
+// anthropicStreamEvent represents the events of a streamed Messages API response.
+// Input tokens arrive with message_start and output tokens with message_delta.
+type anthropicStreamEvent struct {
+	Type    string `json:"type"`
+	Message struct {
+		Usage anthropicUsage `json:"usage"`
+	} `json:"message"`
+	Delta struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"delta"`
+	Usage anthropicUsage `json:"usage"`
+	Error struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (u anthropicUsage) toUsage() Usage {
+	return Usage{
+		PromptTokens:     u.InputTokens,
+		CompletionTokens: u.OutputTokens,
+		TotalTokens:      u.InputTokens + u.OutputTokens,
+	}
+}
+
+func newAnthropicProvider(pc config.ProviderConfig, client *http.Client) *anthropicProvider {
+	endpoint := pc.APIEndpoint
+	if endpoint == "" {
+		endpoint = defaultAnthropicEndpoint
+	}
+	return &anthropicProvider{endpoint: strings.TrimSuffix(endpoint, "/"), apiKey: pc.APIKey, client: client}
+}
+
+func (p *anthropicProvider) header() http.Header {
+	return http.Header{
+		"X-Api-Key":         {p.apiKey},
+		"Anthropic-Version": {anthropicVersion},
+	}
+}
+
+// request converts a chat request: system messages move to the top-level system field
+func (p *anthropicProvider) request(req *ChatRequest, stream bool) *anthropicRequest {
+	out := &anthropicRequest{Model: req.Model, MaxTokens: anthropicMaxTokens, Stream: stream}
+	var system []string
+	for _, m := range req.Messages {
+		if m.Role == "system" {
+			system = append(system, m.Content)
+			continue
+		}
+		out.Messages = append(out.Messages, m)
+	}
+	out.System = strings.Join(system, "\n\n")
+	return out
+}
+
+// Chat sends a Messages API request
+func (p *anthropicProvider) Chat(ctx context.Context, req *ChatRequest) (*Completion, error) {
+	var resp anthropicResponse
+	if err := postJSON(ctx, p.client, p.endpoint+"/messages", p.header(), p.request(req, false), &resp); err != nil {
+		return nil, err
+	}
+
+	var text strings.Builder
+	for _, block := range resp.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+	if text.Len() == 0 {
+		return nil, fmt.Errorf("no completion generated")
+	}
+	return &Completion{Content: text.String(), Usage: resp.Usage.toUsage()}, nil
+}
+
+// ChatStream sends a streamed Messages API request and relays the text deltas
+func (p *anthropicProvider) ChatStream(ctx context.Context, req *ChatRequest, onDelta DeltaFunc) (*Completion, error) {
+	resp, err := post(ctx, p.client, p.endpoint+"/messages", p.header(), p.request(req, true))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var usage anthropicUsage
+	content, err := readSSE(resp.Body, onDelta, func(data string) (string, bool, error) {
+		var ev anthropicStreamEvent
+		if err := json.Unmarshal([]byte(data), &ev); err != nil {
+			return "", false, fmt.Errorf("failed to decode stream event: %v", err)
+		}
+		switch ev.Type {
+		case "message_start":
+			usage.InputTokens = ev.Message.Usage.InputTokens
+		case "message_delta":
+			usage.OutputTokens = ev.Usage.OutputTokens
+		case "content_block_delta":
+			if ev.Delta.Type == "text_delta" {
+				return ev.Delta.Text, false, nil
+			}
+		case "message_stop":
+			return "", true, nil
+		case "error":
+			return "", false, fmt.Errorf("stream error: %s", ev.Error.Message)
+		}
+		return "", false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Completion{Content: content, Usage: usage.toUsage()}, nil
+}
+
+// Embed is not offered by the Anthropic API
+func (p *anthropicProvider) Embed(ctx context.Context, model string, inputs []string) (*Embeddings, error) {
+	return nil, fmt.Errorf("anthropic: embeddings: %w", ErrNotSupported)
+}
@@ -0,0 +1,64 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ncecere/reader-go/internal/common/config"
+)
+
+// defaultAzureAPIVersion is used when api_version is not configured
+const defaultAzureAPIVersion = "2024-06-01"
+
+// azureProvider talks to Azure OpenAI. Models are addressed by deployment name in the
+// URL and the key is sent in the api-key header.
+type azureProvider struct {
+	endpoint   string
+	apiKey     string
+	apiVersion string
+	client     *http.Client
+}
+
+func newAzureProvider(pc config.ProviderConfig, client *http.Client) (*azureProvider, error) {
+	if pc.APIEndpoint == "" {
+		return nil, fmt.Errorf("azure provider requires api_endpoint (https://<resource>.openai.azure.com)")
+	}
+	version := pc.APIVersion
+	if version == "" {
+		version = defaultAzureAPIVersion
+	}
+	return &azureProvider{
+		endpoint:   strings.TrimSuffix(pc.APIEndpoint, "/"),
+		apiKey:     pc.APIKey,
+		apiVersion: version,
+		client:     client,
+	}, nil
+}
+
+// deploymentURL returns the URL of an operation on a deployment
+func (p *azureProvider) deploymentURL(deployment, operation string) string {
+	return fmt.Sprintf("%s/openai/deployments/%s/%s?api-version=%s",
+		p.endpoint, url.PathEscape(deployment), operation, url.QueryEscape(p.apiVersion))
+}
+
+func (p *azureProvider) header() http.Header {
+	return http.Header{"Api-Key": {p.apiKey}}
+}
+
+// Chat sends a chat completion request to the deployment named by req.Model
+func (p *azureProvider) Chat(ctx context.Context, req *ChatRequest) (*Completion, error) {
+	return openAIChat(ctx, p.client, p.deploymentURL(req.Model, "chat/completions"), p.header(), req)
+}
+
+// ChatStream sends a streamed chat completion request to the deployment named by req.Model
+func (p *azureProvider) ChatStream(ctx context.Context, req *ChatRequest, onDelta DeltaFunc) (*Completion, error) {
+	return openAIChatStream(ctx, p.client, p.deploymentURL(req.Model, "chat/completions"), p.header(), req, onDelta)
+}
+
+// Embed sends an embeddings request to the deployment named by model
+func (p *azureProvider) Embed(ctx context.Context, model string, inputs []string) (*Embeddings, error) {
+	return openAIEmbed(ctx, p.client, p.deploymentURL(model, "embeddings"), p.header(), &EmbeddingRequest{Input: inputs})
+}
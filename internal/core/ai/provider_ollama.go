@@ -0,0 +1,155 @@
+package ai
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ncecere/reader-go/internal/common/config"
+)
+
+// defaultOllamaEndpoint is used when api_endpoint is not configured
+const defaultOllamaEndpoint = "http://localhost:11434"
+
+// ollamaProvider talks to the native Ollama API
+type ollamaProvider struct {
+	endpoint string
+	client   *http.Client
+}
+
+// ollamaChatRequest represents an /api/chat request
+type ollamaChatRequest struct {
+	Model    string    `json:"model"`
+	Messages []Message `json:"messages"`
+	Stream   bool      `json:"stream"`
+	Format   string    `json:"format,omitempty"`
+}
+
+// ollamaChatResponse represents an /api/chat response, or one line of a streamed response
+type ollamaChatResponse struct {
+	Message struct {
+		Content string `json:"content"`
+	} `json:"message"`
+	Done  bool   `json:"done"`
+	Error string `json:"error"`
+
+	PromptEvalCount int `json:"prompt_eval_count"`
+	EvalCount       int `json:"eval_count"`
+}
+
+func (r *ollamaChatResponse) usage() Usage {
+	return Usage{
+		PromptTokens:     r.PromptEvalCount,
+		CompletionTokens: r.EvalCount,
+		TotalTokens:      r.PromptEvalCount + r.EvalCount,
+	}
+}
+
+// ollamaEmbedRequest represents an /api/embed request
+type ollamaEmbedRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+// ollamaEmbedResponse represents an /api/embed response
+type ollamaEmbedResponse struct {
+	Embeddings      [][]float64 `json:"embeddings"`
+	PromptEvalCount int         `json:"prompt_eval_count"`
+}
+
+func newOllamaProvider(pc config.ProviderConfig, client *http.Client) *ollamaProvider {
+	endpoint := pc.APIEndpoint
+	if endpoint == "" {
+		endpoint = defaultOllamaEndpoint
+	}
+	return &ollamaProvider{endpoint: strings.TrimSuffix(endpoint, "/"), client: client}
+}
+
+func (p *ollamaProvider) request(req *ChatRequest, stream bool) *ollamaChatRequest {
+	out := &ollamaChatRequest{Model: req.Model, Messages: req.Messages, Stream: stream}
+	if req.ResponseFormat != nil && req.ResponseFormat.Type == "json_object" {
+		out.Format = "json"
+	}
+	return out
+}
+
+// Chat sends an /api/chat request
+func (p *ollamaProvider) Chat(ctx context.Context, req *ChatRequest) (*Completion, error) {
+	var resp ollamaChatResponse
+	if err := postJSON(ctx, p.client, p.endpoint+"/api/chat", nil, p.request(req, false), &resp); err != nil {
+		return nil, err
+	}
+	if resp.Error != "" {
+		return nil, fmt.Errorf("ollama error: %s", resp.Error)
+	}
+	if resp.Message.Content == "" {
+		return nil, fmt.Errorf("no completion generated")
+	}
+	return &Completion{Content: resp.Message.Content, Usage: resp.usage()}, nil
+}
+
+// ChatStream sends a streamed /api/chat request; Ollama streams one JSON object per line
+func (p *ollamaProvider) ChatStream(ctx context.Context, req *ChatRequest, onDelta DeltaFunc) (*Completion, error) {
+	resp, err := post(ctx, p.client, p.endpoint+"/api/chat", nil, p.request(req, true))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var full strings.Builder
+	var usage Usage
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxStreamLine)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var chunk ollamaChatResponse
+		if err := json.Unmarshal([]byte(line), &chunk); err != nil {
+			return nil, fmt.Errorf("failed to decode stream chunk: %v", err)
+		}
+		if chunk.Error != "" {
+			return nil, fmt.Errorf("ollama error: %s", chunk.Error)
+		}
+		if delta := chunk.Message.Content; delta != "" {
+			full.WriteString(delta)
+			if err := onDelta(delta); err != nil {
+				return nil, err
+			}
+		}
+		if chunk.Done {
+			usage = chunk.usage()
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read stream: %v", err)
+	}
+
+	if full.Len() == 0 {
+		return nil, fmt.Errorf("no completion generated")
+	}
+
+	return &Completion{Content: full.String(), Usage: usage}, nil
+}
+
+// Embed sends an /api/embed request
+func (p *ollamaProvider) Embed(ctx context.Context, model string, inputs []string) (*Embeddings, error) {
+	var resp ollamaEmbedResponse
+	if err := postJSON(ctx, p.client, p.endpoint+"/api/embed", nil, &ollamaEmbedRequest{Model: model, Input: inputs}, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Embeddings) != len(inputs) {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(inputs), len(resp.Embeddings))
+	}
+	return &Embeddings{
+		Vectors: resp.Embeddings,
+		Usage:   Usage{PromptTokens: resp.PromptEvalCount, TotalTokens: resp.PromptEvalCount},
+	}, nil
+}
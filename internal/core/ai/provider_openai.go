@@ -0,0 +1,176 @@
+package ai
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxStreamLine bounds the size of a single streamed line from the upstream API
+const maxStreamLine = 1024 * 1024
+
+// openAIProvider talks to OpenAI and OpenAI-compatible APIs (vLLM, LiteLLM, LocalAI, ...)
+type openAIProvider struct {
+	endpoint string
+	apiKey   string
+	client   *http.Client
+}
+
+// ChatStreamChunk represents one server-sent event of a streamed chat completion.
+// Some servers report usage in the final chunk.
+type ChatStreamChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+	} `json:"choices"`
+	Usage *Usage `json:"usage"`
+}
+
+// EmbeddingRequest represents the OpenAI embeddings request
+type EmbeddingRequest struct {
+	Model string   `json:"model,omitempty"`
+	Input []string `json:"input"`
+}
+
+// EmbeddingResponse represents the OpenAI embeddings response
+type EmbeddingResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float64 `json:"embedding"`
+	} `json:"data"`
+	Usage Usage `json:"usage"`
+}
+
+func (p *openAIProvider) header() http.Header {
+	return http.Header{"Authorization": {"Bearer " + p.apiKey}}
+}
+
+// Chat sends a chat completion request
+func (p *openAIProvider) Chat(ctx context.Context, req *ChatRequest) (*Completion, error) {
+	return openAIChat(ctx, p.client, p.endpoint+"/chat/completions", p.header(), req)
+}
+
+// ChatStream sends a streamed chat completion request
+func (p *openAIProvider) ChatStream(ctx context.Context, req *ChatRequest, onDelta DeltaFunc) (*Completion, error) {
+	return openAIChatStream(ctx, p.client, p.endpoint+"/chat/completions", p.header(), req, onDelta)
+}
+
+// Embed sends an embeddings request
+func (p *openAIProvider) Embed(ctx context.Context, model string, inputs []string) (*Embeddings, error) {
+	return openAIEmbed(ctx, p.client, p.endpoint+"/embeddings", p.header(), &EmbeddingRequest{Model: model, Input: inputs})
+}
+
+// openAIChat posts an OpenAI-shaped chat request; shared with Azure OpenAI
+func openAIChat(ctx context.Context, client *http.Client, url string, header http.Header, req *ChatRequest) (*Completion, error) {
+	var chatResp ChatResponse
+	if err := postJSON(ctx, client, url, header, req, &chatResp); err != nil {
+		return nil, err
+	}
+
+	if len(chatResp.Choices) == 0 {
+		return nil, fmt.Errorf("no completion generated")
+	}
+
+	return &Completion{Content: chatResp.Choices[0].Message.Content, Usage: chatResp.Usage}, nil
+}
+
+// openAIChatStream posts a streamed chat request and parses the SSE deltas
+func openAIChatStream(ctx context.Context, client *http.Client, url string, header http.Header, req *ChatRequest, onDelta DeltaFunc) (*Completion, error) {
+	streamReq := *req
+	streamReq.Stream = true
+
+	resp, err := post(ctx, client, url, header, &streamReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var usage Usage
+	content, err := readSSE(resp.Body, onDelta, func(data string) (string, bool, error) {
+		if data == "[DONE]" {
+			return "", true, nil
+		}
+		var chunk ChatStreamChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return "", false, fmt.Errorf("failed to decode stream chunk: %v", err)
+		}
+		if chunk.Usage != nil {
+			usage = *chunk.Usage
+		}
+		if len(chunk.Choices) == 0 {
+			return "", false, nil
+		}
+		return chunk.Choices[0].Delta.Content, false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Completion{Content: content, Usage: usage}, nil
+}
+
+// openAIEmbed posts an OpenAI-shaped embeddings request and orders the vectors by index
+func openAIEmbed(ctx context.Context, client *http.Client, url string, header http.Header, req *EmbeddingRequest) (*Embeddings, error) {
+	var resp EmbeddingResponse
+	if err := postJSON(ctx, client, url, header, req, &resp); err != nil {
+		return nil, err
+	}
+
+	if len(resp.Data) != len(req.Input) {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(req.Input), len(resp.Data))
+	}
+
+	vectors := make([][]float64, len(req.Input))
+	for _, d := range resp.Data {
+		if d.Index < 0 || d.Index >= len(vectors) {
+			return nil, fmt.Errorf("embedding index %d out of range", d.Index)
+		}
+		vectors[d.Index] = d.Embedding
+	}
+	return &Embeddings{Vectors: vectors, Usage: resp.Usage}, nil
+}
+
+// sseHandler decodes the data of one server-sent event into a text delta; done ends the stream
+type sseHandler func(data string) (delta string, done bool, err error)
+
+// readSSE reads a server-sent event stream, passing each data line to handle
+// and relaying non-empty deltas to onDelta
+func readSSE(body io.Reader, onDelta DeltaFunc, handle sseHandler) (string, error) {
+	var full strings.Builder
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxStreamLine)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "data:") {
+			continue // blank separators, comments and event names
+		}
+
+		delta, done, err := handle(strings.TrimSpace(strings.TrimPrefix(line, "data:")))
+		if err != nil {
+			return "", err
+		}
+		if delta != "" {
+			full.WriteString(delta)
+			if err := onDelta(delta); err != nil {
+				return "", err
+			}
+		}
+		if done {
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("failed to read stream: %v", err)
+	}
+
+	if full.Len() == 0 {
+		return "", fmt.Errorf("no completion generated")
+	}
+
+	return full.String(), nil
+}
@@ -0,0 +1,199 @@
+package ai_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ncecere/reader-go/internal/common/config"
+	"github.com/ncecere/reader-go/internal/core/ai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnthropicProvider(t *testing.T) {
+	var body map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/messages", r.URL.Path)
+		assert.Equal(t, "test-key", r.Header.Get("x-api-key"))
+		assert.NotEmpty(t, r.Header.Get("anthropic-version"))
+		assert.Empty(t, r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"content": []map[string]string{{"type": "text", "text": "A short summary."}},
+		})
+	}))
+	defer ts.Close()
+
+	cfg := newTestConfig(ts.URL + "/v1")
+	cfg.AI.Provider = "anthropic"
+	summary, err := ai.NewService(cfg).Summarize(context.Background(), "Long text.", nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "A short summary.", summary)
+	assert.Equal(t, "test-model", body["model"])
+	assert.Equal(t, "Summarize:", body["system"])
+	assert.NotZero(t, body["max_tokens"])
+	messages := body["messages"].([]interface{})
+	require.Len(t, messages, 1)
+	assert.Equal(t, "user", messages[0].(map[string]interface{})["role"])
+}
+
+func TestAnthropicProviderStream(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, ev := range []string{
+			`{"type":"message_start"}`,
+			`{"type":"content_block_delta","delta":{"type":"text_delta","text":"Hello"}}`,
+			`{"type":"content_block_delta","delta":{"type":"text_delta","text":" world"}}`,
+			`{"type":"message_stop"}`,
+		} {
+			fmt.Fprintf(w, "event: x\ndata: %s\n\n", ev)
+		}
+	}))
+	defer ts.Close()
+
+	cfg := newTestConfig(ts.URL)
+	cfg.AI.Provider = "anthropic"
+	var deltas []string
+	summary, err := ai.NewService(cfg).SummarizeStream(context.Background(), "text", nil, func(d string) error {
+		deltas = append(deltas, d)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Hello world", summary)
+	assert.Equal(t, []string{"Hello", " world"}, deltas)
+}
+
+func TestAnthropicProviderEmbedNotSupported(t *testing.T) {
+	cfg := newTestConfig("http://unused")
+	cfg.AI.Provider = "anthropic"
+	_, err := ai.NewService(cfg).Embed(context.Background(), []string{"text"})
+	assert.True(t, errors.Is(err, ai.ErrNotSupported))
+}
+
+func TestAzureProvider(t *testing.T) {
+	var paths []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path+"?"+r.URL.RawQuery)
+		assert.Equal(t, "test-key", r.Header.Get("api-key"))
+		assert.Empty(t, r.Header.Get("Authorization"))
+		if r.URL.Path == "/openai/deployments/embed-deploy/embeddings" {
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": []map[string]interface{}{{"index": 0, "embedding": []float64{0.5}}},
+			})
+			return
+		}
+		writeChatReply(w, "Azure summary.")
+	}))
+	defer ts.Close()
+
+	cfg := newTestConfig(ts.URL)
+	cfg.AI.Provider = "azure"
+	cfg.AI.Model = "chat-deploy"
+	cfg.AI.EmbeddingModel = "embed-deploy"
+	cfg.AI.APIVersion = "2024-10-21"
+	svc := ai.NewService(cfg)
+
+	summary, err := svc.Summarize(context.Background(), "text", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Azure summary.", summary)
+
+	vectors, err := svc.Embed(context.Background(), []string{"text"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float64{{0.5}}, vectors)
+
+	assert.Equal(t, []string{
+		"/openai/deployments/chat-deploy/chat/completions?api-version=2024-10-21",
+		"/openai/deployments/embed-deploy/embeddings?api-version=2024-10-21",
+	}, paths)
+}
+
+func TestOllamaProvider(t *testing.T) {
+	var chat map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/chat":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&chat))
+			if chat["stream"] == true {
+				fmt.Fprintln(w, `{"message":{"content":"Loc"},"done":false}`)
+				fmt.Fprintln(w, `{"message":{"content":"al"},"done":false}`)
+				fmt.Fprintln(w, `{"message":{"content":""},"done":true}`)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"message": map[string]string{"role": "assistant", "content": "Local summary."},
+				"done":    true,
+			})
+		case "/api/embed":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"embeddings": [][]float64{{1}, {2}}})
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	cfg := newTestConfig(ts.URL)
+	cfg.AI.Provider = "ollama"
+	cfg.AI.EmbeddingModel = "nomic-embed-text"
+	svc := ai.NewService(cfg)
+
+	summary, err := svc.Summarize(context.Background(), "text", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Local summary.", summary)
+	assert.Equal(t, false, chat["stream"])
+
+	streamed, err := svc.SummarizeStream(context.Background(), "other text", nil, func(string) error { return nil })
+	require.NoError(t, err)
+	assert.Equal(t, "Local", streamed)
+
+	vectors, err := svc.Embed(context.Background(), []string{"a", "b"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float64{{1}, {2}}, vectors)
+}
+
+func TestProviderSelectedPerRequest(t *testing.T) {
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeChatReply(w, "from primary")
+	}))
+	defer primary.Close()
+	local := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"message": map[string]string{"content": "from local"},
+		})
+	}))
+	defer local.Close()
+
+	cfg := newTestConfig(primary.URL)
+	cfg.AI.Providers = map[string]config.ProviderConfig{
+		"local": {Type: "ollama", APIEndpoint: local.URL, Model: "llama3.1"},
+	}
+	svc := ai.NewService(cfg)
+	assert.Equal(t, []string{"default", "local"}, svc.Providers())
+
+	summary, err := svc.Summarize(context.Background(), "text", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "from primary", summary)
+
+	// Same text, different provider: must not be served from the primary's cache
+	summary, err = svc.Summarize(ai.WithProvider(context.Background(), "local"), "text", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "from local", summary)
+
+	_, err = svc.Summarize(ai.WithProvider(context.Background(), "missing"), "text", nil)
+	assert.True(t, errors.Is(err, ai.ErrUnknownProvider))
+}
+
+func TestNewProviderUnknownType(t *testing.T) {
+	_, err := ai.NewProvider(config.ProviderConfig{Type: "bard"}, http.DefaultClient)
+	assert.Error(t, err)
+
+	cfg := newTestConfig("http://unused")
+	cfg.AI.Provider = "bard"
+	_, err = ai.NewService(cfg).Summarize(context.Background(), "text", nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,65 @@
+package ai
+
+import (
+	"context"
+
+	"github.com/ncecere/reader-go/internal/common/metrics"
+	"github.com/ncecere/reader-go/internal/core/redact"
+)
+
+// redactMessages masks sensitive data in user messages before they are sent to a provider.
+// System prompts and earlier model replies are left as they are.
+func (s *Service) redactMessages(ctx context.Context, messages []Message) []Message {
+	if s.redactor == nil {
+		return messages
+	}
+
+	redacted := make([]Message, len(messages))
+	for i, m := range messages {
+		if m.Role == "user" {
+			m.Content = s.redact(ctx, m.Content)
+		}
+		redacted[i] = m
+	}
+	return redacted
+}
+
+// redact masks sensitive data in text and records the counts on the request's tracker
+func (s *Service) redact(ctx context.Context, text string) string {
+	if s.redactor == nil {
+		return text
+	}
+
+	text, counts := s.redactor.Redact(text)
+	for rule, n := range counts {
+		metrics.Redactions.WithLabelValues(rule, "ai").Add(float64(n))
+	}
+	if tracker := UsageTrackerFrom(ctx); tracker != nil && counts.Total() > 0 {
+		tracker.mu.Lock()
+		tracker.redactions.Add(counts)
+		tracker.mu.Unlock()
+	}
+	return text
+}
+
+// redacted returns text as the model saw it, for checking model output against the input
+func (s *Service) redacted(text string) string {
+	if s.redactor == nil {
+		return text
+	}
+	text, _ = s.redactor.Redact(text)
+	return text
+}
+
+// Redactions returns how many matches of each redaction rule were masked before AI calls.
+// It is safe to call on a nil tracker.
+func (t *UsageTracker) Redactions() redact.Counts {
+	counts := make(redact.Counts)
+	if t == nil {
+		return counts
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	counts.Add(t.redactions)
+	return counts
+}
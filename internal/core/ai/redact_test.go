@@ -0,0 +1,80 @@
+package ai_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/ncecere/reader-go/internal/common/config"
+	"github.com/ncecere/reader-go/internal/core/ai"
+	"github.com/ncecere/reader-go/internal/core/redact"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarizeRedactsBeforeSending(t *testing.T) {
+	var sent []ai.Message
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ai.ChatRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		sent = req.Messages
+		writeChatReply(w, "summary")
+	}))
+	defer ts.Close()
+
+	cfg := newTestConfig(ts.URL)
+	cfg.Redaction.Enabled = true
+	cfg.Redaction.Custom = []config.RedactionRuleConfig{{Name: "ticket", Pattern: `TCK-\d+`}}
+	svc := ai.NewService(cfg)
+
+	tracker := ai.NewUsageTracker("summary", "")
+	ctx := ai.WithUsageTracker(context.Background(), tracker)
+	_, err := svc.Summarize(ctx, "Mail bob@example.com or call +44 20 7946 0958 about TCK-42.", nil)
+	require.NoError(t, err)
+
+	require.Len(t, sent, 2)
+	assert.Equal(t, "Summarize:", sent[0].Content)
+	assert.Equal(t, "Mail [EMAIL] or call [PHONE] about [TICKET].", sent[1].Content)
+	assert.Equal(t, redact.Counts{"email": 1, "phone": 1, "ticket": 1}, tracker.Redactions())
+}
+
+func TestEmbedRedactsInputs(t *testing.T) {
+	var mu sync.Mutex
+	var inputs []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Input []string `json:"input"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		mu.Lock()
+		inputs = append(inputs, req.Input...)
+		mu.Unlock()
+
+		data := make([]map[string]interface{}, len(req.Input))
+		for i := range req.Input {
+			data[i] = map[string]interface{}{"index": i, "embedding": []float64{1}}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+	}))
+	defer ts.Close()
+
+	cfg := newTestConfig(ts.URL)
+	cfg.Redaction.Enabled = true
+	cfg.Redaction.Rules = []string{"ip"}
+
+	_, err := ai.NewService(cfg).Embed(context.Background(), []string{"server 10.0.0.1, owner a@b.io"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"server [IP], owner a@b.io"}, inputs)
+}
+
+func TestInvalidRedactionConfigBlocksAICalls(t *testing.T) {
+	cfg := newTestConfig("http://127.0.0.1:1")
+	cfg.Redaction.Enabled = true
+	cfg.Redaction.Rules = []string{"passport"}
+
+	_, err := ai.NewService(cfg).Summarize(context.Background(), "text", nil)
+	assert.ErrorContains(t, err, "invalid redaction configuration")
+}
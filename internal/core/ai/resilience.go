@@ -0,0 +1,246 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ncecere/reader-go/internal/common/metrics"
+	"github.com/ncecere/reader-go/internal/core/proxy"
+)
+
+const (
+	// defaultTimeout bounds each upstream attempt until response headers arrive
+	defaultTimeout = 120 * time.Second
+	// defaultMaxRetries is used when ai.max_retries is not configured
+	defaultMaxRetries = 3
+	// retryBaseDelay is the first backoff delay; each retry doubles it up to retryMaxDelay
+	retryBaseDelay = 250 * time.Millisecond
+	retryMaxDelay  = 10 * time.Second
+	// maxRetryAfter is the longest Retry-After we wait out; longer waits fail over instead
+	maxRetryAfter = 60 * time.Second
+
+	// defaultBreakerThreshold consecutive failures open a provider's circuit
+	defaultBreakerThreshold = 5
+	// defaultBreakerCooldown is how long an open circuit rejects requests before a trial request
+	defaultBreakerCooldown = 30 * time.Second
+)
+
+// ErrCircuitOpen is returned when a provider's circuit breaker is rejecting requests
+var ErrCircuitOpen = errors.New("AI provider circuit open")
+
+// APIError is a non-200 response from an upstream AI API
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API request failed with status %d: %s", e.StatusCode, e.Body)
+}
+
+// retryableStatus reports whether an upstream status is worth retrying
+func retryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= 500
+}
+
+// isUnavailable reports whether err means the provider could not serve the request
+// (transport failure, rate limiting or a server error) rather than a bad request
+func isUnavailable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return retryableStatus(apiErr.StatusCode)
+	}
+	return true
+}
+
+// retryTransport retries requests that fail with a transport error, 429 or 5xx,
+// backing off exponentially and honoring Retry-After
+type retryTransport struct {
+	base       http.RoundTripper
+	provider   string
+	maxRetries int
+}
+
+// newHTTPClient returns a client for one provider with per-attempt timeouts and retries,
+// connecting through proxies if any are configured
+func newHTTPClient(provider string, timeout time.Duration, maxRetries int, proxies *proxy.Manager) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = timeout
+	return &http.Client{
+		Transport: &retryTransport{base: proxies.Transport(transport), provider: provider, maxRetries: maxRetries},
+	}
+}
+
+// RoundTrip sends req, retrying retryable failures
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	for attempt := 0; ; attempt++ {
+		r := req
+		if attempt > 0 && req.Body != nil {
+			if req.GetBody == nil {
+				return nil, fmt.Errorf("cannot retry request without GetBody")
+			}
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			r = req.Clone(ctx)
+			r.Body = body
+		}
+
+		resp, err := t.base.RoundTrip(r)
+
+		var status string
+		switch {
+		case err != nil:
+			status = "error"
+		case retryableStatus(resp.StatusCode):
+			status = strconv.Itoa(resp.StatusCode)
+		default:
+			return resp, nil
+		}
+		if attempt >= t.maxRetries || ctx.Err() != nil {
+			return resp, err
+		}
+
+		delay := backoff(attempt)
+		if resp != nil {
+			if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+				if wait > maxRetryAfter {
+					return resp, nil
+				}
+				delay = wait
+			}
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+			resp.Body.Close()
+		}
+
+		metrics.AIRequestRetries.WithLabelValues(t.provider, status).Inc()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns the delay before retry attempt+1: exponential with jitter
+func backoff(attempt int) time.Duration {
+	d := retryBaseDelay << uint(attempt)
+	if d <= 0 || d > retryMaxDelay {
+		d = retryMaxDelay
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(at); wait > 0 {
+			return wait, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// breakerState is the state of a circuit breaker, as exported in metrics
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerHalfOpen
+	breakerOpen
+)
+
+// breaker is a per-provider circuit breaker. After threshold consecutive failures it
+// opens and rejects requests for cooldown; then a single trial request decides whether
+// it closes again or stays open for another cooldown.
+type breaker struct {
+	mu        sync.Mutex
+	provider  string
+	threshold int
+	cooldown  time.Duration
+	state     breakerState
+	failures  int
+	openedAt  time.Time
+}
+
+func newBreaker(provider string, threshold int, cooldown time.Duration) *breaker {
+	b := &breaker{provider: provider, threshold: threshold, cooldown: cooldown}
+	metrics.AICircuitState.WithLabelValues(provider).Set(float64(breakerClosed))
+	return b
+}
+
+// allow reports whether a request may be sent to the provider
+func (b *breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerClosed:
+		return true
+	case breakerOpen:
+		if time.Since(b.openedAt) >= b.cooldown {
+			b.setState(breakerHalfOpen)
+			return true
+		}
+		return false
+	default:
+		// A trial request is already in flight
+		return false
+	}
+}
+
+// record updates the breaker with the outcome of an allowed request
+func (b *breaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch {
+	case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+		// The caller gave up; say nothing about the provider but free the trial slot
+		if b.state == breakerHalfOpen {
+			b.setState(breakerOpen)
+		}
+	case isUnavailable(err):
+		b.failures++
+		if b.state == breakerHalfOpen || b.failures >= b.threshold {
+			b.openedAt = time.Now()
+			if b.state != breakerOpen {
+				metrics.AICircuitOpens.WithLabelValues(b.provider).Inc()
+			}
+			b.setState(breakerOpen)
+		}
+	default:
+		b.failures = 0
+		b.setState(breakerClosed)
+	}
+}
+
+func (b *breaker) setState(state breakerState) {
+	b.state = state
+	metrics.AICircuitState.WithLabelValues(b.provider).Set(float64(state))
+}
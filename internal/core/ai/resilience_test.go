@@ -0,0 +1,159 @@
+package ai_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ncecere/reader-go/internal/common/config"
+	"github.com/ncecere/reader-go/internal/common/metrics"
+	"github.com/ncecere/reader-go/internal/core/ai"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFlakyServer fails the first `failures` requests with status (and Retry-After if set)
+func newFlakyServer(failures int32, status int, retryAfter string, reply string) (*httptest.Server, *int32) {
+	var calls int32
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= failures {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			http.Error(w, "unavailable", status)
+			return
+		}
+		writeChatReply(w, reply)
+	})), &calls
+}
+
+func TestRetryHonorsRetryAfter(t *testing.T) {
+	ts, calls := newFlakyServer(2, http.StatusTooManyRequests, "0", "ok")
+	defer ts.Close()
+
+	summary, err := ai.NewService(newTestConfig(ts.URL)).Summarize(context.Background(), "text", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", summary)
+	assert.Equal(t, int32(3), atomic.LoadInt32(calls))
+}
+
+func TestRetryBacksOffOnServerError(t *testing.T) {
+	ts, calls := newFlakyServer(1, http.StatusBadGateway, "", "ok")
+	defer ts.Close()
+
+	summary, err := ai.NewService(newTestConfig(ts.URL)).Summarize(context.Background(), "text", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", summary)
+	assert.Equal(t, int32(2), atomic.LoadInt32(calls))
+}
+
+func TestNoRetryOnClientError(t *testing.T) {
+	ts, calls := newFlakyServer(1, http.StatusBadRequest, "", "ok")
+	defer ts.Close()
+
+	_, err := ai.NewService(newTestConfig(ts.URL)).Summarize(context.Background(), "text", nil)
+	var apiErr *ai.APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+}
+
+func TestFallbackChain(t *testing.T) {
+	primary, primaryCalls := newFlakyServer(1000, http.StatusTooManyRequests, "3600", "primary")
+	defer primary.Close()
+
+	var backupModel string
+	backup := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ai.ChatRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		backupModel = req.Model
+		writeChatReply(w, "backup")
+	}))
+	defer backup.Close()
+
+	cfg := newTestConfig(primary.URL)
+	cfg.AI.Providers = map[string]config.ProviderConfig{
+		"backup": {APIEndpoint: backup.URL, Model: "backup-model"},
+	}
+	cfg.AI.Fallbacks = []config.FallbackConfig{{Provider: "backup", Model: "small-model"}}
+
+	summary, err := ai.NewService(cfg).Summarize(context.Background(), "text", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "backup", summary)
+	assert.Equal(t, "small-model", backupModel)
+	// A Retry-After beyond the retry budget fails over immediately
+	assert.Equal(t, int32(1), atomic.LoadInt32(primaryCalls))
+}
+
+func TestFallbackUnknownProvider(t *testing.T) {
+	cfg := newTestConfig("http://unused")
+	cfg.AI.Fallbacks = []config.FallbackConfig{{Provider: "missing"}}
+
+	_, err := ai.NewService(cfg).Summarize(context.Background(), "text", nil)
+	assert.Error(t, err)
+}
+
+func TestCircuitBreakerOpens(t *testing.T) {
+	primary, primaryCalls := newFlakyServer(1000, http.StatusInternalServerError, "", "primary")
+	defer primary.Close()
+	backup, _ := newFlakyServer(0, 0, "", "backup")
+	defer backup.Close()
+
+	cfg := newTestConfig(primary.URL)
+	cfg.AI.MaxRetries = -1
+	cfg.AI.BreakerThreshold = 2
+	cfg.AI.BreakerCooldown = 3600
+	cfg.AI.Providers = map[string]config.ProviderConfig{"backup": {APIEndpoint: backup.URL, Model: "m"}}
+	cfg.AI.Fallbacks = []config.FallbackConfig{{Provider: "backup"}}
+	svc := ai.NewService(cfg)
+
+	for _, text := range []string{"one", "two", "three", "four"} {
+		summary, err := svc.Summarize(context.Background(), text, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "backup", summary)
+	}
+
+	// The primary is not contacted once its circuit is open
+	assert.Equal(t, int32(2), atomic.LoadInt32(primaryCalls))
+	assert.Equal(t, 2.0, testutil.ToFloat64(metrics.AICircuitState.WithLabelValues("default")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(metrics.AICircuitState.WithLabelValues("backup")))
+}
+
+func TestCircuitOpenWithoutFallback(t *testing.T) {
+	primary, _ := newFlakyServer(1000, http.StatusServiceUnavailable, "", "primary")
+	defer primary.Close()
+
+	cfg := newTestConfig(primary.URL)
+	cfg.AI.MaxRetries = -1
+	cfg.AI.BreakerThreshold = 1
+	cfg.AI.BreakerCooldown = 3600
+	svc := ai.NewService(cfg)
+
+	_, err := svc.Summarize(context.Background(), "one", nil)
+	require.Error(t, err)
+	_, err = svc.Summarize(context.Background(), "two", nil)
+	assert.True(t, errors.Is(err, ai.ErrCircuitOpen))
+}
+
+func TestStreamFallsBackBeforeFirstDelta(t *testing.T) {
+	primary, _ := newFlakyServer(1000, http.StatusServiceUnavailable, "3600", "primary")
+	defer primary.Close()
+	backup := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("data: {\"choices\":[{\"delta\":{\"content\":\"backup\"}}]}\n\ndata: [DONE]\n\n"))
+	}))
+	defer backup.Close()
+
+	cfg := newTestConfig(primary.URL)
+	cfg.AI.Providers = map[string]config.ProviderConfig{"backup": {APIEndpoint: backup.URL, Model: "m"}}
+	cfg.AI.Fallbacks = []config.FallbackConfig{{Provider: "backup"}}
+
+	summary, err := ai.NewService(cfg).SummarizeStream(context.Background(), "text", nil, func(string) error { return nil })
+	require.NoError(t, err)
+	assert.Equal(t, "backup", summary)
+}
@@ -1,20 +1,32 @@
 package ai
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
+	"strings"
+	"time"
 
 	"github.com/ncecere/reader-go/internal/common/config"
+	"github.com/ncecere/reader-go/internal/common/logger"
+	"github.com/ncecere/reader-go/internal/core/cache"
+	"github.com/ncecere/reader-go/internal/core/redact"
+	"go.uber.org/zap"
 )
 
 // Service handles AI-related operations
 type Service struct {
-	config *config.Config
-	client *http.Client
+	config       *config.Config
+	summaries    *cache.Cache
+	embeddings   *cache.Cache
+	translations *cache.Cache
+
+	backends   map[string]*backend
+	backendErr error
+	budget     *budget
+	redactor   *redact.Redactor
 }
 
 // Message represents a chat message
@@ -23,10 +35,19 @@ type Message struct {
 	Content string `json:"content"`
 }
 
-// ChatRequest represents the OpenAI chat completion request
+// ChatRequest represents the OpenAI chat completion request. It is also the
+// provider-neutral request shape that other providers translate from.
 type ChatRequest struct {
 	Model    string    `json:"model"`
 	Messages []Message `json:"messages"`
+	Stream   bool      `json:"stream,omitempty"`
+
+	ResponseFormat *ResponseFormat `json:"response_format,omitempty"`
+}
+
+// ResponseFormat asks the model for a particular output format, e.g. {"type": "json_object"}
+type ResponseFormat struct {
+	Type string `json:"type"`
 }
 
 // ChatResponse represents the OpenAI chat completion response
@@ -36,73 +57,131 @@ type ChatResponse struct {
 			Content string `json:"content"`
 		} `json:"message"`
 	} `json:"choices"`
+	Usage Usage `json:"usage"`
 }
 
 // NewService creates a new AI service
 func NewService(cfg *config.Config) *Service {
-	return &Service{
-		config: cfg,
-		client: &http.Client{},
+	backends, err := newBackends(cfg)
+	if err != nil {
+		// Surface the problem on every AI call rather than failing startup for non-AI routes
+		logger.Log.Error("Invalid AI provider configuration", zap.Error(err))
+		err = fmt.Errorf("invalid AI provider configuration: %w", err)
 	}
-}
 
-// Summarize generates a summary of the provided text using the configured AI model
-func (s *Service) Summarize(ctx context.Context, text string) (string, error) {
-	if !s.config.AI.Enabled {
-		return "", fmt.Errorf("AI summarization is not enabled")
+	var redactor *redact.Redactor
+	if cfg.Redaction.Enabled {
+		var redactErr error
+		redactor, redactErr = redact.New(cfg.Redaction.Rules, cfg.Redaction.Custom)
+		if redactErr != nil {
+			// Refuse AI calls rather than send unredacted content
+			logger.Log.Error("Invalid redaction configuration", zap.Error(redactErr))
+			err = errors.Join(err, fmt.Errorf("invalid redaction configuration: %w", redactErr))
+		}
 	}
 
-	messages := []Message{
-		{Role: "system", Content: s.config.AI.Prompt},
-		{Role: "user", Content: text},
+	return &Service{
+		config:     cfg,
+		backends:   backends,
+		backendErr: err,
+		budget:     newBudget(cfg.AI.DailyTokenBudget, cfg.AI.KeyBudgets),
+		redactor:   redactor,
+		summaries: cache.New(&cache.Options{
+			MaxAge:   1 * time.Hour,
+			MaxItems: 1000,
+		}),
+		embeddings: cache.New(&cache.Options{
+			MaxAge:   24 * time.Hour,
+			MaxItems: 10000,
+		}),
+		translations: cache.New(&cache.Options{
+			MaxAge:   24 * time.Hour,
+			MaxItems: 1000,
+		}),
 	}
+}
 
-	reqBody := ChatRequest{
-		Model:    s.config.AI.Model,
-		Messages: messages,
+// Summarize generates a summary of the provided text using the configured AI model.
+// A nil opts uses the default prompt.
+func (s *Service) Summarize(ctx context.Context, text string, opts *SummaryOptions) (string, error) {
+	if !s.config.AI.Enabled {
+		return "", fmt.Errorf("AI summarization is not enabled")
 	}
 
-	jsonData, err := json.Marshal(reqBody)
+	prompt, err := s.resolvePrompt(opts)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %v", err)
+		return "", err
 	}
 
-	endpoint := fmt.Sprintf("%s/chat/completions", s.config.AI.APIEndpoint)
-	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewBuffer(jsonData))
+	b, err := s.backend(ctx)
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %v", err)
+		return "", err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.config.AI.APIKey))
+	key := s.summaryKey(b, prompt, text)
+	if summary, found := s.summaries.Get(key); found {
+		return summary, nil
+	}
 
-	resp, err := s.client.Do(req)
+	// Long documents are condensed chunk by chunk so they fit the model's context window
+	input, err := s.condense(ctx, text)
 	if err != nil {
-		return "", fmt.Errorf("failed to make request: %v", err)
+		return "", err
 	}
-	defer resp.Body.Close()
 
-	// Read response body for error cases
-	body, err := io.ReadAll(resp.Body)
+	summary, err := s.complete(ctx, prompt, input)
 	if err != nil {
-		return "", fmt.Errorf("failed to read response body: %v", err)
+		return "", err
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
-	}
+	s.summaries.Set(key, summary)
+	return summary, nil
+}
 
-	// Create new reader from body for JSON decoding
-	resp.Body = io.NopCloser(bytes.NewBuffer(body))
+// summaryKey hashes the backend, model, prompt and text so each combination is cached separately
+func (s *Service) summaryKey(b *backend, prompt, text string) string {
+	hash := sha256.Sum256([]byte(b.cacheID() + "\x00" + prompt + "\x00" + text))
+	return hex.EncodeToString(hash[:])
+}
 
-	var chatResp ChatResponse
-	if err := json.NewDecoder(resp.Body).Decode(&chatResp); err != nil {
-		return "", fmt.Errorf("failed to decode response: %v", err)
+// complete sends a system prompt and user message to the selected provider
+func (s *Service) complete(ctx context.Context, system, user string) (string, error) {
+	messages := []Message{
+		{Role: "system", Content: system},
+		{Role: "user", Content: user},
 	}
 
-	if len(chatResp.Choices) == 0 {
-		return "", fmt.Errorf("no summary generated")
-	}
+	return s.chat(ctx, ChatRequest{
+		Messages: messages,
+	})
+}
 
-	return chatResp.Choices[0].Message.Content, nil
+// chat sends a chat request to the provider selected for ctx, falling back to the
+// configured fallbacks if it fails
+func (s *Service) chat(ctx context.Context, req ChatRequest) (string, error) {
+	req.Messages = s.redactMessages(ctx, req.Messages)
+
+	var content string
+	err := s.withFallback(ctx, true, func(t target) (bool, error) {
+		attempt := req
+		attempt.Model = t.model
+
+		completion, err := t.backend.provider.Chat(ctx, &attempt)
+		if err != nil {
+			return false, err
+		}
+		s.recordUsage(ctx, t.model, completion.Usage, messagesText(attempt.Messages), completion.Content)
+		content = completion.Content
+		return false, nil
+	})
+	return content, err
+}
+
+// messagesText joins message contents, for estimating prompt tokens
+func messagesText(messages []Message) string {
+	var b strings.Builder
+	for _, m := range messages {
+		b.WriteString(m.Content)
+	}
+	return b.String()
 }
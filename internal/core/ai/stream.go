@@ -0,0 +1,74 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+)
+
+// DeltaFunc receives each piece of generated text as it arrives.
+// Returning an error aborts the stream.
+type DeltaFunc func(delta string) error
+
+// SummarizeStream works like Summarize but relays the final completion to onDelta as it
+// is generated. The full summary is returned and cached once the stream completes.
+func (s *Service) SummarizeStream(ctx context.Context, text string, opts *SummaryOptions, onDelta DeltaFunc) (string, error) {
+	if !s.config.AI.Enabled {
+		return "", fmt.Errorf("AI summarization is not enabled")
+	}
+
+	prompt, err := s.resolvePrompt(opts)
+	if err != nil {
+		return "", err
+	}
+
+	b, err := s.backend(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	key := s.summaryKey(b, prompt, text)
+	if summary, found := s.summaries.Get(key); found {
+		return summary, onDelta(summary)
+	}
+
+	input, err := s.condense(ctx, text)
+	if err != nil {
+		return "", err
+	}
+
+	summary, err := s.completeStream(ctx, prompt, input, onDelta)
+	if err != nil {
+		return "", err
+	}
+
+	s.summaries.Set(key, summary)
+	return summary, nil
+}
+
+// completeStream requests a streamed chat completion from the selected provider. It falls
+// back to the next provider only if the failed stream had not yet produced any text.
+func (s *Service) completeStream(ctx context.Context, system, user string, onDelta DeltaFunc) (string, error) {
+	user = s.redact(ctx, user)
+
+	var content string
+	err := s.withFallback(ctx, true, func(t target) (bool, error) {
+		started := false
+		completion, err := t.backend.provider.ChatStream(ctx, &ChatRequest{
+			Model: t.model,
+			Messages: []Message{
+				{Role: "system", Content: system},
+				{Role: "user", Content: user},
+			},
+		}, func(delta string) error {
+			started = true
+			return onDelta(delta)
+		})
+		if err != nil {
+			return started, err
+		}
+		s.recordUsage(ctx, t.model, completion.Usage, system+user, completion.Content)
+		content = completion.Content
+		return true, nil
+	})
+	return content, err
+}
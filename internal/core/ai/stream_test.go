@@ -0,0 +1,71 @@
+package ai_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ncecere/reader-go/internal/core/ai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarizeStream(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+
+		var req ai.ChatRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.True(t, req.Stream)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, token := range []string{"Hello", ", ", "world"} {
+			fmt.Fprintf(w, "data: {\"choices\":[{\"delta\":{\"content\":%q}}]}\n\n", token)
+		}
+		fmt.Fprint(w, ": keep-alive comment\n\n")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{},\"finish_reason\":\"stop\"}]}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer ts.Close()
+
+	svc := ai.NewService(newTestConfig(ts.URL))
+
+	var deltas []string
+	summary, err := svc.SummarizeStream(context.Background(), "page text", nil, func(delta string) error {
+		deltas = append(deltas, delta)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Hello, world", summary)
+	assert.Equal(t, []string{"Hello", ", ", "world"}, deltas)
+
+	// The completed stream is cached for both streaming and regular requests
+	cached, err := svc.Summarize(context.Background(), "page text", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Hello, world", cached)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestSummarizeStreamAbort(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"a\"}}]}\n\n")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"b\"}}]}\n\n")
+	}))
+	defer ts.Close()
+
+	svc := ai.NewService(newTestConfig(ts.URL))
+
+	_, err := svc.SummarizeStream(context.Background(), "page text", nil, func(string) error {
+		return fmt.Errorf("client went away")
+	})
+	assert.EqualError(t, err, "client went away")
+
+	// A failed stream must not be cached
+	_, err = svc.SummarizeStream(context.Background(), "page text", nil, func(string) error { return nil })
+	assert.NoError(t, err)
+}
@@ -0,0 +1,165 @@
+package ai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ncecere/reader-go/internal/common/logger"
+	"github.com/ncecere/reader-go/internal/core/chunker"
+	"github.com/ncecere/reader-go/internal/core/langdetect"
+	"go.uber.org/zap"
+)
+
+// ErrInvalidLanguage is returned when a target language is empty or not a plausible name or tag
+var ErrInvalidLanguage = errors.New("invalid target language")
+
+var (
+	// languagePattern accepts tags like "de", "pt-BR" and names like "Brazilian Portuguese"
+	languagePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z _-]{1,34}$`)
+	fencePattern    = regexp.MustCompile("^[ \t]*(```+|~~~+)")
+	inlineCode      = regexp.MustCompile("`[^`\n]+`")
+	linkTarget      = regexp.MustCompile(`\]\([^)\s]+(?:\s+"[^"]*")?\)`)
+	autoLink        = regexp.MustCompile(`<https?://[^>\s]+>`)
+	bareURL         = regexp.MustCompile(`https?://[^\s)\]>"]+`)
+)
+
+// translatePrompt is the system prompt for translating one chunk of markdown
+const translatePrompt = "You are a professional translator. Translate the markdown the user sends %s into %s. " +
+	"Preserve the markdown structure exactly: headings, lists, tables, emphasis and line breaks. " +
+	"Tokens of the form ⟦n⟧ stand for code and URLs: copy them unchanged and keep them in place. " +
+	"Output only the translated markdown, with no commentary."
+
+// Translate translates markdown into targetLang chunk by chunk. Fenced code blocks, inline
+// code and URLs are replaced with placeholders before translation and restored afterwards.
+// sourceLang may be empty if unknown; when it matches targetLang the text is returned as is.
+func (s *Service) Translate(ctx context.Context, markdown, sourceLang, targetLang string) (string, error) {
+	if !s.config.AI.Enabled {
+		return "", fmt.Errorf("AI features are not enabled")
+	}
+
+	targetLang = strings.TrimSpace(targetLang)
+	if !languagePattern.MatchString(targetLang) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidLanguage, targetLang)
+	}
+	if sourceLang != "" && langdetect.Normalize(sourceLang) == langdetect.Normalize(targetLang) {
+		return markdown, nil
+	}
+
+	b, err := s.backend(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	key := s.translationKey(b, targetLang, markdown)
+	if translated, found := s.translations.Get(key); found {
+		return translated, nil
+	}
+
+	protected, originals := protectMarkdown(markdown)
+
+	// Translations are about as long as their input, so leave room for the reply
+	chunks, err := chunker.Split(protected, "", &chunker.Options{
+		Size: s.maxInputTokens() / 2,
+		Unit: chunker.Tokens,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to chunk text: %w", err)
+	}
+
+	from := "from " + sourceLang
+	if sourceLang == "" {
+		from = "from its original language"
+	}
+	parts, err := s.mapChunks(ctx, fmt.Sprintf(translatePrompt, from, targetLang), chunks)
+	if err != nil {
+		return "", err
+	}
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+
+	translated, missing := restoreMarkdown(strings.Join(parts, "\n\n"), originals)
+	if missing > 0 {
+		logger.Log.Warn("Translation dropped protected code or URLs",
+			zap.String("target_lang", targetLang),
+			zap.Int("missing", missing))
+	}
+
+	s.translations.Set(key, translated)
+	return translated, nil
+}
+
+// translationKey hashes the backend, model, target language and text so each translation is cached separately
+func (s *Service) translationKey(b *backend, targetLang, text string) string {
+	hash := sha256.Sum256([]byte(b.cacheID() + "\x00" + strings.ToLower(targetLang) + "\x00" + text))
+	return hex.EncodeToString(hash[:])
+}
+
+// protectMarkdown replaces fenced code blocks, inline code and URLs with numbered
+// placeholders. It returns the protected text and the original of each placeholder.
+func protectMarkdown(markdown string) (string, []string) {
+	var originals []string
+	hold := func(s string) string {
+		originals = append(originals, s)
+		return "⟦" + strconv.Itoa(len(originals)-1) + "⟧"
+	}
+
+	// Fenced code blocks are replaced whole, line by line so fences inside them are ignored
+	var b strings.Builder
+	var block strings.Builder
+	fence := ""
+	for _, line := range strings.SplitAfter(markdown, "\n") {
+		m := fencePattern.FindStringSubmatch(line)
+		switch {
+		case fence == "" && m != nil:
+			fence = m[1]
+			block.WriteString(line)
+		case fence != "":
+			block.WriteString(line)
+			if m != nil && strings.HasPrefix(m[1], fence) && strings.TrimSpace(line) == m[1] {
+				fence = ""
+				text := block.String()
+				newline := strings.HasSuffix(text, "\n")
+				b.WriteString(hold(strings.TrimSuffix(text, "\n")))
+				if newline {
+					b.WriteString("\n")
+				}
+				block.Reset()
+			}
+		default:
+			b.WriteString(line)
+		}
+	}
+	// An unterminated fence runs to the end of the document
+	if block.Len() > 0 {
+		b.WriteString(hold(block.String()))
+	}
+
+	text := b.String()
+	for _, re := range []*regexp.Regexp{inlineCode, linkTarget, autoLink, bareURL} {
+		text = re.ReplaceAllStringFunc(text, hold)
+	}
+	return text, originals
+}
+
+// restoreMarkdown puts the originals back in place of their placeholders. It works in
+// reverse so placeholders captured inside later replacements are resolved too, and
+// returns the restored text and the number of placeholders missing from it.
+func restoreMarkdown(text string, originals []string) (string, int) {
+	missing := 0
+	for i := len(originals) - 1; i >= 0; i-- {
+		token := "⟦" + strconv.Itoa(i) + "⟧"
+		if !strings.Contains(text, token) {
+			missing++
+			continue
+		}
+		text = strings.ReplaceAll(text, token, originals[i])
+	}
+	return text, missing
+}
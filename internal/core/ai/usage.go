@@ -0,0 +1,224 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ncecere/reader-go/internal/common/config"
+	"github.com/ncecere/reader-go/internal/common/metrics"
+	"github.com/ncecere/reader-go/internal/core/chunker"
+	"github.com/ncecere/reader-go/internal/core/redact"
+)
+
+// ErrBudgetExceeded is returned when an API key has used its daily token budget
+var ErrBudgetExceeded = errors.New("daily token budget exceeded")
+
+// Usage counts the tokens used by one or more AI calls and their estimated cost
+type Usage struct {
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	TotalTokens      int     `json:"total_tokens"`
+	EstimatedCost    float64 `json:"estimated_cost_usd,omitempty"`
+}
+
+func (u *Usage) add(other Usage) {
+	u.PromptTokens += other.PromptTokens
+	u.CompletionTokens += other.CompletionTokens
+	u.TotalTokens += other.TotalTokens
+	u.EstimatedCost += other.EstimatedCost
+}
+
+// UsageTracker accumulates the usage of every AI call made for one API request,
+// along with the redactions applied to the content sent
+type UsageTracker struct {
+	mu         sync.Mutex
+	endpoint   string
+	apiKey     string
+	usage      Usage
+	redactions redact.Counts
+	reserved   int // budget tokens held for calls not yet made; guarded by budget.mu
+}
+
+// NewUsageTracker creates a tracker for a request to endpoint made with apiKey
+func NewUsageTracker(endpoint, apiKey string) *UsageTracker {
+	return &UsageTracker{endpoint: endpoint, apiKey: apiKey, redactions: make(redact.Counts)}
+}
+
+// Usage returns the usage recorded so far. It is safe to call on a nil tracker.
+func (t *UsageTracker) Usage() Usage {
+	if t == nil {
+		return Usage{}
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.usage
+}
+
+type usageKey struct{}
+
+// WithUsageTracker returns a context whose AI calls are recorded in t
+func WithUsageTracker(ctx context.Context, t *UsageTracker) context.Context {
+	return context.WithValue(ctx, usageKey{}, t)
+}
+
+// UsageTrackerFrom returns the tracker attached to ctx, or nil
+func UsageTrackerFrom(ctx context.Context) *UsageTracker {
+	t, _ := ctx.Value(usageKey{}).(*UsageTracker)
+	return t
+}
+
+// recordUsage prices a call's usage, exports it and charges it to the request's tracker
+// and API key budget. Providers that report no usage are estimated from text length.
+func (s *Service) recordUsage(ctx context.Context, model string, usage Usage, prompt, completion string) {
+	if usage.PromptTokens == 0 && usage.CompletionTokens == 0 && usage.TotalTokens == 0 {
+		usage.PromptTokens = chunker.EstimateTokens(prompt)
+		usage.CompletionTokens = chunker.EstimateTokens(completion)
+	}
+	if usage.TotalTokens == 0 {
+		usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+	}
+	if price, ok := s.price(model); ok {
+		usage.EstimatedCost = (float64(usage.PromptTokens)*price.Prompt +
+			float64(usage.CompletionTokens)*price.Completion) / 1e6
+	}
+
+	tracker := UsageTrackerFrom(ctx)
+	endpoint := "unknown"
+	if tracker != nil {
+		endpoint = tracker.endpoint
+		tracker.mu.Lock()
+		tracker.usage.add(usage)
+		tracker.mu.Unlock()
+		s.budget.consume(tracker, usage.TotalTokens)
+	}
+
+	metrics.AITokens.WithLabelValues(model, endpoint, "prompt").Add(float64(usage.PromptTokens))
+	metrics.AITokens.WithLabelValues(model, endpoint, "completion").Add(float64(usage.CompletionTokens))
+	metrics.AICost.WithLabelValues(model, endpoint).Add(usage.EstimatedCost)
+}
+
+// price looks up a model's price. Viper lowercases map keys, so matching ignores case.
+func (s *Service) price(model string) (config.PriceConfig, bool) {
+	if price, ok := s.config.AI.Prices[model]; ok {
+		return price, true
+	}
+	for name, price := range s.config.AI.Prices {
+		if strings.EqualFold(name, model) {
+			return price, true
+		}
+	}
+	return config.PriceConfig{}, false
+}
+
+// ReserveBudget returns an error wrapping ErrBudgetExceeded once t's API key has used
+// its daily token budget. Otherwise it reserves an estimate of the request's tokens, up
+// to ai.max_input_tokens, so concurrent requests cannot all pass on the same remainder.
+// Calls made for t are charged against the reservation; ReleaseBudget returns what is
+// left of it. Budgets reset at midnight UTC.
+func (s *Service) ReserveBudget(t *UsageTracker) error {
+	return s.budget.reserve(t, s.maxInputTokens())
+}
+
+// ReleaseBudget returns the part of t's reservation its calls did not use
+func (s *Service) ReleaseBudget(t *UsageTracker) {
+	s.budget.release(t)
+}
+
+// budget tracks tokens used per UTC day. Keys listed in ai.key_budgets have their own
+// bucket; all other keys, and requests without one, share the default budget, so a new
+// key does not get a fresh allowance.
+type budget struct {
+	mu     sync.Mutex
+	day    string
+	shared *bucket
+	keys   map[string]*bucket
+}
+
+// bucket is a daily token limit and what is used and reserved against it; 0 is unlimited
+type bucket struct {
+	limit    int
+	used     int
+	reserved int
+}
+
+// newBudget creates a budget. Per-key limits match keys case-insensitively because
+// viper lowercases map keys loaded from configuration.
+func newBudget(defaultLimit int, limits map[string]int) *budget {
+	keys := make(map[string]*bucket, len(limits))
+	for key, limit := range limits {
+		keys[strings.ToLower(key)] = &bucket{limit: limit}
+	}
+	return &budget{shared: &bucket{limit: defaultLimit}, keys: keys}
+}
+
+// bucketFor returns the bucket key is charged to
+func (b *budget) bucketFor(key string) *bucket {
+	if bk, ok := b.keys[strings.ToLower(key)]; ok {
+		return bk
+	}
+	return b.shared
+}
+
+// rollover starts a new day's accounting; reservations of requests in flight carry
+// over. The caller must hold b.mu.
+func (b *budget) rollover() {
+	if today := time.Now().UTC().Format("2006-01-02"); today != b.day {
+		b.day = today
+		b.shared.used = 0
+		for _, bk := range b.keys {
+			bk.used = 0
+		}
+	}
+}
+
+func (b *budget) reserve(t *UsageTracker, estimate int) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	bk := b.bucketFor(t.apiKey)
+	if bk.limit <= 0 {
+		return nil
+	}
+	b.rollover()
+	committed := bk.used + bk.reserved
+	if committed >= bk.limit {
+		return fmt.Errorf("%w: used %d and reserved %d of %d tokens", ErrBudgetExceeded, bk.used, bk.reserved, bk.limit)
+	}
+	if remaining := bk.limit - committed; estimate > remaining {
+		estimate = remaining
+	}
+	bk.reserved += estimate
+	t.reserved += estimate
+	return nil
+}
+
+// consume charges tokens used by a call for t, first against t's reservation
+func (b *budget) consume(t *UsageTracker, tokens int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	bk := b.bucketFor(t.apiKey)
+	if bk.limit <= 0 {
+		return
+	}
+	b.rollover()
+	held := t.reserved
+	if held > tokens {
+		held = tokens
+	}
+	t.reserved -= held
+	bk.reserved -= held
+	bk.used += tokens
+}
+
+func (b *budget) release(t *UsageTracker) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.bucketFor(t.apiKey).reserved -= t.reserved
+	t.reserved = 0
+}
@@ -0,0 +1,165 @@
+package ai_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ncecere/reader-go/internal/common/config"
+	"github.com/ncecere/reader-go/internal/core/ai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeChatReplyWithUsage writes a chat completion response that reports token usage
+func writeChatReplyWithUsage(w http.ResponseWriter, content string, prompt, completion int) {
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"choices": []map[string]interface{}{{"message": map[string]string{"content": content}}},
+		"usage": map[string]int{
+			"prompt_tokens":     prompt,
+			"completion_tokens": completion,
+			"total_tokens":      prompt + completion,
+		},
+	})
+}
+
+func TestUsageIsTrackedAndPriced(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeChatReplyWithUsage(w, "summary", 1000, 200)
+	}))
+	defer ts.Close()
+
+	cfg := newTestConfig(ts.URL)
+	cfg.AI.Prices = map[string]config.PriceConfig{"Test-Model": {Prompt: 0.5, Completion: 1.5}}
+	svc := ai.NewService(cfg)
+
+	tracker := ai.NewUsageTracker("summary", "key-1")
+	ctx := ai.WithUsageTracker(context.Background(), tracker)
+	_, err := svc.Summarize(ctx, "text", nil)
+	require.NoError(t, err)
+
+	usage := tracker.Usage()
+	assert.Equal(t, 1000, usage.PromptTokens)
+	assert.Equal(t, 200, usage.CompletionTokens)
+	assert.Equal(t, 1200, usage.TotalTokens)
+	assert.InDelta(t, (1000*0.5+200*1.5)/1e6, usage.EstimatedCost, 1e-12)
+
+	// Cached summaries cost nothing
+	_, err = svc.Summarize(ctx, "text", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1200, tracker.Usage().TotalTokens)
+}
+
+func TestUsageAccumulatesAcrossCalls(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeChatReplyWithUsage(w, "partial", 10, 5)
+	}))
+	defer ts.Close()
+
+	cfg := newTestConfig(ts.URL)
+	cfg.AI.MaxInputTokens = 100
+	tracker := ai.NewUsageTracker("summary", "")
+	ctx := ai.WithUsageTracker(context.Background(), tracker)
+
+	text := strings.Repeat("A sentence about things. ", 100)
+	_, err := ai.NewService(cfg).Summarize(ctx, text, nil)
+	require.NoError(t, err)
+
+	usage := tracker.Usage()
+	assert.Greater(t, usage.TotalTokens, 15, "map and reduce calls should all be counted")
+	assert.Equal(t, 0, usage.TotalTokens%15)
+}
+
+func TestUsageEstimatedWhenNotReported(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeChatReply(w, strings.Repeat("x", 40))
+	}))
+	defer ts.Close()
+
+	tracker := ai.NewUsageTracker("summary", "")
+	ctx := ai.WithUsageTracker(context.Background(), tracker)
+	_, err := ai.NewService(newTestConfig(ts.URL)).Summarize(ctx, strings.Repeat("y", 400), nil)
+	require.NoError(t, err)
+
+	usage := tracker.Usage()
+	assert.GreaterOrEqual(t, usage.PromptTokens, 100)
+	assert.Equal(t, 10, usage.CompletionTokens)
+}
+
+func TestDailyTokenBudget(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeChatReplyWithUsage(w, "summary", 600, 100)
+	}))
+	defer ts.Close()
+
+	cfg := newTestConfig(ts.URL)
+	cfg.AI.DailyTokenBudget = 1000
+	cfg.AI.KeyBudgets = map[string]int{"vip": 0, "Team-A": 1000}
+	svc := ai.NewService(cfg)
+
+	summarize := func(key, text string) error {
+		tracker := ai.NewUsageTracker("summary", key)
+		if err := svc.ReserveBudget(tracker); err != nil {
+			return err
+		}
+		defer svc.ReleaseBudget(tracker)
+		_, err := svc.Summarize(ai.WithUsageTracker(context.Background(), tracker), text, nil)
+		require.NoError(t, err)
+		return nil
+	}
+
+	require.NoError(t, summarize("team-a", "one"))
+	require.NoError(t, summarize("team-a", "two"))
+	assert.ErrorIs(t, summarize("team-a", "three"), ai.ErrBudgetExceeded)
+
+	require.NoError(t, summarize("guest-1", "four"))
+	require.NoError(t, summarize("guest-2", "five"))
+	assert.ErrorIs(t, summarize("guest-3", "six"), ai.ErrBudgetExceeded,
+		"keys without their own budget share the default one")
+	assert.ErrorIs(t, summarize("", "seven"), ai.ErrBudgetExceeded)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, summarize("VIP", fmt.Sprintf("text %d", i)), "a zero key budget is unlimited")
+	}
+}
+
+func TestBudgetReservation(t *testing.T) {
+	cfg := newTestConfig("http://unused.test")
+	cfg.AI.DailyTokenBudget = 1000
+	cfg.AI.MaxInputTokens = 400
+	svc := ai.NewService(cfg)
+
+	// Concurrent requests hold their estimates, so only the remainder is handed out
+	var held []*ai.UsageTracker
+	for i := 0; i < 3; i++ {
+		tracker := ai.NewUsageTracker("summary", fmt.Sprintf("key-%d", i))
+		require.NoError(t, svc.ReserveBudget(tracker))
+		held = append(held, tracker)
+	}
+	assert.ErrorIs(t, svc.ReserveBudget(ai.NewUsageTracker("summary", "key-3")), ai.ErrBudgetExceeded)
+
+	svc.ReleaseBudget(held[0])
+	assert.NoError(t, svc.ReserveBudget(ai.NewUsageTracker("summary", "key-3")), "released reservations are available again")
+}
+
+func TestAnthropicUsage(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"content": []map[string]string{{"type": "text", "text": "summary"}},
+			"usage":   map[string]int{"input_tokens": 42, "output_tokens": 7},
+		})
+	}))
+	defer ts.Close()
+
+	cfg := newTestConfig(ts.URL)
+	cfg.AI.Provider = "anthropic"
+	tracker := ai.NewUsageTracker("summary", "")
+	_, err := ai.NewService(cfg).Summarize(ai.WithUsageTracker(context.Background(), tracker), "text", nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, ai.Usage{PromptTokens: 42, CompletionTokens: 7, TotalTokens: 49}, tracker.Usage())
+}
@@ -0,0 +1,233 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+	"github.com/chromedp/chromedp/kb"
+)
+
+const (
+	// defaultActionTimeout bounds a step without its own timeout
+	defaultActionTimeout = 10 * time.Second
+	// maxActions bounds the number of steps in one request
+	maxActions = 50
+)
+
+// Action is one step of a script run on a page before it is read
+type Action struct {
+	// Type is click, type, select, wait_for, scroll, press or evaluate
+	Type string `json:"type"`
+	// Selector is a CSS selector; required for click, type, select and wait_for
+	Selector string `json:"selector,omitempty"`
+	// Value is the text to type or the option value to select
+	Value string `json:"value,omitempty"`
+	// Key is pressed by press, e.g. "Enter", "Tab", "ArrowDown" or a single character
+	Key string `json:"key,omitempty"`
+	// State is what wait_for waits for: visible (default), present, hidden or absent
+	State string `json:"state,omitempty"`
+	// Y is how far scroll scrolls down in pixels without a selector; 0 scrolls to the bottom
+	Y int `json:"y,omitempty"`
+	// Script is the JavaScript run by evaluate; promises are awaited
+	Script string `json:"script,omitempty"`
+	// Timeout is the step timeout in milliseconds (0 = 10 seconds)
+	Timeout int `json:"timeout,omitempty"`
+}
+
+// ActionError reports which step of an action script failed
+type ActionError struct {
+	Step   int // 1-based
+	Action Action
+	Err    error
+}
+
+func (e *ActionError) Error() string {
+	return fmt.Sprintf("step %d (%s) failed: %v", e.Step, e.Action.describe(), e.Err)
+}
+
+func (e *ActionError) Unwrap() error {
+	return e.Err
+}
+
+// describe names a step for error messages
+func (a Action) describe() string {
+	if a.Selector != "" {
+		return fmt.Sprintf("%s %q", a.Type, a.Selector)
+	}
+	if a.Key != "" {
+		return fmt.Sprintf("%s %q", a.Type, a.Key)
+	}
+	return a.Type
+}
+
+// keys maps key names accepted by press to chromedp key codes
+var keys = map[string]string{
+	"enter":      kb.Enter,
+	"tab":        kb.Tab,
+	"escape":     kb.Escape,
+	"backspace":  kb.Backspace,
+	"delete":     kb.Delete,
+	"space":      " ",
+	"arrowup":    kb.ArrowUp,
+	"arrowdown":  kb.ArrowDown,
+	"arrowleft":  kb.ArrowLeft,
+	"arrowright": kb.ArrowRight,
+	"home":       kb.Home,
+	"end":        kb.End,
+	"pageup":     kb.PageUp,
+	"pagedown":   kb.PageDown,
+}
+
+// keyCode returns the chromedp key code for a key name or single character
+func keyCode(name string) (string, bool) {
+	if code, ok := keys[strings.ToLower(name)]; ok {
+		return code, true
+	}
+	if len([]rune(name)) == 1 {
+		return name, true
+	}
+	return "", false
+}
+
+// ValidateActions checks an action script before any of it runs
+func ValidateActions(actions []Action) error {
+	if len(actions) > maxActions {
+		return fmt.Errorf("too many actions: %d (maximum %d)", len(actions), maxActions)
+	}
+	for i, a := range actions {
+		if err := a.validate(); err != nil {
+			return &ActionError{Step: i + 1, Action: a, Err: err}
+		}
+	}
+	return nil
+}
+
+// validate checks that a step has the fields its type needs
+func (a Action) validate() error {
+	switch a.Type {
+	case "click", "type", "select", "wait_for":
+		if a.Selector == "" {
+			return fmt.Errorf("selector is required")
+		}
+	case "scroll":
+	case "press":
+		if _, ok := keyCode(a.Key); !ok {
+			return fmt.Errorf("unknown key %q", a.Key)
+		}
+	case "evaluate":
+		if a.Script == "" {
+			return fmt.Errorf("script is required")
+		}
+	default:
+		return fmt.Errorf("unknown action type %q (want click, type, select, wait_for, scroll, press or evaluate)", a.Type)
+	}
+
+	switch a.State {
+	case "", "visible", "present", "hidden", "absent":
+	default:
+		return fmt.Errorf("unknown state %q (want visible, present, hidden or absent)", a.State)
+	}
+	if a.Timeout < 0 {
+		return fmt.Errorf("timeout must not be negative")
+	}
+	return nil
+}
+
+// RunActions returns an action that runs the steps in order, each within its own
+// timeout. A failed step stops the script with an *ActionError.
+func RunActions(actions []Action) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		for i, a := range actions {
+			timeout := defaultActionTimeout
+			if a.Timeout > 0 {
+				timeout = time.Duration(a.Timeout) * time.Millisecond
+			}
+
+			stepCtx, cancel := context.WithTimeout(ctx, timeout)
+			err := a.step().Do(stepCtx)
+			cancel()
+			if err != nil {
+				return &ActionError{Step: i + 1, Action: a, Err: err}
+			}
+		}
+		return nil
+	})
+}
+
+// step converts an action into chromedp actions. The action must be valid.
+func (a Action) step() chromedp.Action {
+	switch a.Type {
+	case "click":
+		return chromedp.Click(a.Selector, chromedp.ByQuery)
+
+	case "type":
+		return chromedp.SendKeys(a.Selector, a.Value, chromedp.ByQuery)
+
+	case "select":
+		// Setting the value alone does not notify the page's scripts
+		args, _ := json.Marshal([]string{a.Selector, a.Value})
+		return chromedp.Tasks{
+			chromedp.WaitReady(a.Selector, chromedp.ByQuery),
+			chromedp.Evaluate(fmt.Sprintf(`((selector, value) => {
+				const el = document.querySelector(selector);
+				el.value = value;
+				el.dispatchEvent(new Event('input', {bubbles: true}));
+				el.dispatchEvent(new Event('change', {bubbles: true}));
+			}).apply(null, %s)`, args), nil),
+		}
+
+	case "wait_for":
+		switch a.State {
+		case "present":
+			return chromedp.WaitReady(a.Selector, chromedp.ByQuery)
+		case "hidden":
+			return chromedp.WaitNotVisible(a.Selector, chromedp.ByQuery)
+		case "absent":
+			return chromedp.WaitNotPresent(a.Selector, chromedp.ByQuery)
+		}
+		return chromedp.WaitVisible(a.Selector, chromedp.ByQuery)
+
+	case "scroll":
+		if a.Selector != "" {
+			return chromedp.ScrollIntoView(a.Selector, chromedp.ByQuery)
+		}
+		if a.Y != 0 {
+			return chromedp.Evaluate(fmt.Sprintf(`window.scrollBy(0, %d)`, a.Y), nil)
+		}
+		return chromedp.Evaluate(`window.scrollTo(0, document.body.scrollHeight)`, nil)
+
+	case "press":
+		key, _ := keyCode(a.Key)
+		if a.Selector != "" {
+			return chromedp.SendKeys(a.Selector, key, chromedp.ByQuery)
+		}
+		return chromedp.KeyEvent(key)
+
+	case "evaluate":
+		return chromedp.Evaluate(a.Script, nil, func(p *runtime.EvaluateParams) *runtime.EvaluateParams {
+			return p.WithAwaitPromise(true)
+		})
+	}
+	return chromedp.ActionFunc(func(context.Context) error {
+		return fmt.Errorf("unknown action type %q", a.Type)
+	})
+}
+
+type actionsKey struct{}
+
+// WithActions returns a context whose page fetches run actions on the page before
+// reading it
+func WithActions(ctx context.Context, actions []Action) context.Context {
+	return context.WithValue(ctx, actionsKey{}, actions)
+}
+
+// ActionsFrom returns the actions requested for ctx, if any
+func ActionsFrom(ctx context.Context) []Action {
+	actions, _ := ctx.Value(actionsKey{}).([]Action)
+	return actions
+}
@@ -0,0 +1,120 @@
+package browser_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/chromedp/chromedp"
+	"github.com/ncecere/reader-go/internal/core/browser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateActions(t *testing.T) {
+	valid := []browser.Action{
+		{Type: "type", Selector: "#user", Value: "alice"},
+		{Type: "press", Key: "Enter"},
+		{Type: "press", Key: "a", Selector: "#search"},
+		{Type: "wait_for", Selector: ".dashboard", State: "present", Timeout: 5000},
+		{Type: "scroll"},
+		{Type: "select", Selector: "#range", Value: "30d"},
+		{Type: "evaluate", Script: "document.title"},
+		{Type: "click", Selector: "#more"},
+	}
+	assert.NoError(t, browser.ValidateActions(valid))
+	assert.NoError(t, browser.ValidateActions(nil))
+
+	tests := []struct {
+		action browser.Action
+		want   string
+	}{
+		{browser.Action{Type: "hover", Selector: "#x"}, "unknown action type"},
+		{browser.Action{Type: "click"}, "selector is required"},
+		{browser.Action{Type: "press", Key: "Hyperdrive"}, "unknown key"},
+		{browser.Action{Type: "evaluate"}, "script is required"},
+		{browser.Action{Type: "wait_for", Selector: "#x", State: "gone"}, "unknown state"},
+		{browser.Action{Type: "click", Selector: "#x", Timeout: -1}, "timeout"},
+	}
+	for _, tt := range tests {
+		err := browser.ValidateActions([]browser.Action{{Type: "scroll"}, tt.action})
+		require.Error(t, err, tt.want)
+
+		var actionErr *browser.ActionError
+		require.True(t, errors.As(err, &actionErr))
+		assert.Equal(t, 2, actionErr.Step)
+		assert.Contains(t, err.Error(), "step 2")
+		assert.Contains(t, err.Error(), tt.want)
+	}
+
+	tooMany := make([]browser.Action, 51)
+	for i := range tooMany {
+		tooMany[i] = browser.Action{Type: "scroll"}
+	}
+	assert.ErrorContains(t, browser.ValidateActions(tooMany), "too many actions")
+}
+
+func TestActionsFrom(t *testing.T) {
+	ctx := context.Background()
+	assert.Empty(t, browser.ActionsFrom(ctx))
+
+	actions := []browser.Action{{Type: "click", Selector: "#more"}}
+	assert.Equal(t, actions, browser.ActionsFrom(browser.WithActions(ctx, actions)))
+}
+
+func TestRunActions(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`<!DOCTYPE html><html><body>
+			<form id="login" onsubmit="event.preventDefault(); document.getElementById('out').textContent =
+				'Hello ' + this.user.value + ', range ' + document.getElementById('range').value;">
+				<input name="user" id="user">
+				<select id="range"><option value="7d">7 days</option><option value="30d">30 days</option></select>
+			</form>
+			<p id="out"></p>
+			<button id="more" onclick="setTimeout(() => document.body.insertAdjacentHTML('beforeend', '<p class=extra>More</p>'), 200)">Show more</button>
+		</body></html>`))
+	}))
+	defer ts.Close()
+
+	pool := browser.SetupTestPool(t)
+	run := func(actions []browser.Action) (string, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		var text string
+		err := pool.Execute(ctx, func(ctx context.Context) error {
+			return chromedp.Run(ctx,
+				chromedp.Navigate(ts.URL),
+				chromedp.WaitReady("body", chromedp.ByQuery),
+				browser.RunActions(actions),
+				chromedp.Text("body", &text, chromedp.ByQuery),
+			)
+		})
+		return text, err
+	}
+
+	text, err := run([]browser.Action{
+		{Type: "type", Selector: "#user", Value: "alice"},
+		{Type: "select", Selector: "#range", Value: "30d"},
+		{Type: "press", Selector: "#user", Key: "Enter"},
+		{Type: "click", Selector: "#more"},
+		{Type: "wait_for", Selector: ".extra"},
+		{Type: "evaluate", Script: `document.title = 'done'`},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, text, "Hello alice, range 30d")
+	assert.Contains(t, text, "More")
+
+	_, err = run([]browser.Action{
+		{Type: "click", Selector: "#more"},
+		{Type: "wait_for", Selector: "#never", Timeout: 300},
+	})
+	var actionErr *browser.ActionError
+	require.True(t, errors.As(err, &actionErr), "got %v", err)
+	assert.Equal(t, 2, actionErr.Step)
+	assert.Contains(t, err.Error(), `step 2 (wait_for "#never")`)
+}
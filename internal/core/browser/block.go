@@ -0,0 +1,237 @@
+package browser
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/cdproto/network"
+	"github.com/ncecere/reader-go/internal/common/config"
+	"github.com/ncecere/reader-go/internal/common/logger"
+	"github.com/ncecere/reader-go/internal/core/domain"
+	"go.uber.org/zap"
+)
+
+// defaultBlockedTypes are blocked when blocking is enabled without resource_types.
+// None of them affect the text of a page.
+var defaultBlockedTypes = []string{"image", "font", "media"}
+
+// resourceTypes maps configuration names to CDP resource types. Documents are never
+// blocked, or pages and their frames would not load at all.
+var resourceTypes = map[string]network.ResourceType{
+	"stylesheet":  network.ResourceTypeStylesheet,
+	"image":       network.ResourceTypeImage,
+	"media":       network.ResourceTypeMedia,
+	"font":        network.ResourceTypeFont,
+	"script":      network.ResourceTypeScript,
+	"texttrack":   network.ResourceTypeTextTrack,
+	"xhr":         network.ResourceTypeXHR,
+	"fetch":       network.ResourceTypeFetch,
+	"prefetch":    network.ResourceTypePrefetch,
+	"eventsource": network.ResourceTypeEventSource,
+	"websocket":   network.ResourceTypeWebSocket,
+	"manifest":    network.ResourceTypeManifest,
+	"ping":        network.ResourceTypePing,
+	"other":       network.ResourceTypeOther,
+}
+
+// blockRules are the rules that apply to one page
+type blockRules struct {
+	types   map[network.ResourceType]bool
+	domains map[string]bool // extra domains blocked on this site
+	allow   map[string]bool // domains never blocked on this site
+}
+
+// Blocker decides which sub-resources of a page are not loaded
+type Blocker struct {
+	defaults blockRules
+	domains  map[string]bool // domains from the configuration and hosts file
+	sites    map[string]*blockRules
+}
+
+// NewBlocker creates a blocker from the configuration. It returns nil if blocking is
+// disabled; a nil Blocker blocks nothing.
+func NewBlocker(cfg config.BlockingConfig) (*Blocker, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	names := cfg.ResourceTypes
+	if len(names) == 0 {
+		names = defaultBlockedTypes
+	}
+	types, err := parseResourceTypes(names)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Blocker{
+		defaults: blockRules{types: types},
+		domains:  domainSet(cfg.Domains),
+		sites:    make(map[string]*blockRules, len(cfg.Sites)),
+	}
+
+	if cfg.HostsFile != "" {
+		hosts, err := loadHostsFile(cfg.HostsFile)
+		if err != nil {
+			return nil, err
+		}
+		for domain := range hosts {
+			b.domains[domain] = true
+		}
+	}
+
+	for site, sc := range cfg.Sites {
+		rules := &blockRules{
+			types:   types,
+			domains: domainSet(sc.Domains),
+			allow:   domainSet(sc.Allow),
+		}
+		switch {
+		case sc.Disabled:
+			rules = nil
+		case sc.ResourceTypes != nil:
+			if rules.types, err = parseResourceTypes(sc.ResourceTypes); err != nil {
+				return nil, fmt.Errorf("blocking rules for %s: %w", site, err)
+			}
+		}
+		b.sites[domain.Normalize(site)] = rules
+	}
+
+	logger.Log.Info("Resource blocking enabled",
+		zap.Strings("resource_types", names),
+		zap.Int("domains", len(b.domains)),
+		zap.Int("sites", len(b.sites)))
+	return b, nil
+}
+
+// rulesFor returns the rules for a page: those of the most specific matching site,
+// or the defaults. It returns nil if blocking is disabled for the page or b is nil.
+func (b *Blocker) rulesFor(pageURL string) *blockRules {
+	if b == nil {
+		return nil
+	}
+	host := domain.Hostname(pageURL)
+	for _, parent := range domain.Parents(host) {
+		if rules, ok := b.sites[parent]; ok {
+			return rules
+		}
+	}
+	return &b.defaults
+}
+
+// blocked reports whether a request should be failed and why ("type" or "domain")
+func (b *Blocker) blocked(rules *blockRules, resourceType network.ResourceType, requestURL string) (bool, string) {
+	if rules == nil || resourceType == network.ResourceTypeDocument {
+		return false, ""
+	}
+	if rules.types[resourceType] {
+		return true, "type"
+	}
+
+	host := domain.Hostname(requestURL)
+	var listed bool
+	for _, parent := range domain.Parents(host) {
+		if rules.allow[parent] {
+			return false, ""
+		}
+		if b.domains[parent] || rules.domains[parent] {
+			listed = true
+		}
+	}
+	if listed {
+		return true, "domain"
+	}
+	return false, ""
+}
+
+// patterns returns the requests Chrome has to pause for the rules. Without domain
+// rules only the blocked resource types are intercepted.
+func (b *Blocker) patterns(rules *blockRules) []*fetch.RequestPattern {
+	if len(b.domains) > 0 || len(rules.domains) > 0 {
+		return []*fetch.RequestPattern{{URLPattern: "*", RequestStage: fetch.RequestStageRequest}}
+	}
+
+	patterns := make([]*fetch.RequestPattern, 0, len(rules.types))
+	for resourceType := range rules.types {
+		patterns = append(patterns, &fetch.RequestPattern{
+			URLPattern:   "*",
+			ResourceType: resourceType,
+			RequestStage: fetch.RequestStageRequest,
+		})
+	}
+	return patterns
+}
+
+// parseResourceTypes converts configuration names such as "image" to resource types
+func parseResourceTypes(names []string) (map[network.ResourceType]bool, error) {
+	types := make(map[network.ResourceType]bool, len(names))
+	for _, name := range names {
+		resourceType, ok := resourceTypes[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("unknown resource type %q", name)
+		}
+		types[resourceType] = true
+	}
+	return types, nil
+}
+
+// loadHostsFile reads the domains of a hosts-style blocklist. Both "0.0.0.0 ads.example"
+// lines and bare domains are accepted; comments and local names are skipped.
+func loadHostsFile(path string) (map[string]bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open hosts file: %w", err)
+	}
+	defer file.Close()
+
+	domains := make(map[string]bool)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if net.ParseIP(fields[0]) != nil {
+			fields = fields[1:]
+		}
+		for _, field := range fields {
+			name := domain.Normalize(field)
+			if isLocalName(name) || net.ParseIP(name) != nil {
+				continue
+			}
+			domains[name] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read hosts file: %w", err)
+	}
+	return domains, nil
+}
+
+// isLocalName reports whether a hosts file entry names the local machine
+func isLocalName(domain string) bool {
+	switch domain {
+	case "", "localhost", "localhost.localdomain", "local", "broadcasthost":
+		return true
+	}
+	return strings.HasPrefix(domain, "ip6-")
+}
+
+// domainSet builds a set of normalized domains
+func domainSet(domains []string) map[string]bool {
+	set := make(map[string]bool, len(domains))
+	for _, name := range domains {
+		if name = domain.Normalize(name); name != "" {
+			set[name] = true
+		}
+	}
+	return set
+}
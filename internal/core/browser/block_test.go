@@ -0,0 +1,134 @@
+package browser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/ncecere/reader-go/internal/common/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBlocker(t *testing.T, cfg config.BlockingConfig) *Blocker {
+	cleanup := setupTestLogger(t)
+	t.Cleanup(cleanup)
+
+	cfg.Enabled = true
+	b, err := NewBlocker(cfg)
+	require.NoError(t, err)
+	return b
+}
+
+func TestBlockerDisabled(t *testing.T) {
+	b, err := NewBlocker(config.BlockingConfig{ResourceTypes: []string{"image"}})
+	require.NoError(t, err)
+	assert.Nil(t, b)
+}
+
+func TestBlockerDefaultTypes(t *testing.T) {
+	b := newTestBlocker(t, config.BlockingConfig{})
+	rules := b.rulesFor("https://example.com/article")
+
+	for _, resourceType := range []network.ResourceType{network.ResourceTypeImage, network.ResourceTypeFont, network.ResourceTypeMedia} {
+		blocked, reason := b.blocked(rules, resourceType, "https://example.com/asset")
+		assert.True(t, blocked, resourceType)
+		assert.Equal(t, "type", reason)
+	}
+
+	blocked, _ := b.blocked(rules, network.ResourceTypeScript, "https://example.com/app.js")
+	assert.False(t, blocked)
+	blocked, _ = b.blocked(rules, network.ResourceTypeDocument, "https://example.com/")
+	assert.False(t, blocked, "documents are never blocked")
+
+	// Only the blocked types are intercepted when there is no domain list
+	assert.Len(t, b.patterns(rules), 3)
+}
+
+func TestBlockerRejectsUnknownType(t *testing.T) {
+	_, err := NewBlocker(config.BlockingConfig{Enabled: true, ResourceTypes: []string{"pictures"}})
+	assert.ErrorContains(t, err, "pictures")
+}
+
+func TestBlockerHostsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hosts")
+	require.NoError(t, os.WriteFile(path, []byte(`# Ad servers
+127.0.0.1 localhost
+::1 ip6-localhost ip6-loopback
+0.0.0.0 ads.example.net tracker.example.org # inline comment
+analytics.example.io
+`), 0o644))
+
+	b := newTestBlocker(t, config.BlockingConfig{
+		ResourceTypes: []string{"font"},
+		Domains:       []string{"*.doubleclick.net"},
+		HostsFile:     path,
+	})
+	rules := b.rulesFor("https://news.example.com/")
+
+	tests := []struct {
+		url     string
+		blocked bool
+	}{
+		{"https://ads.example.net/banner.js", true},
+		{"https://cdn.ads.example.net/banner.js", true},
+		{"https://tracker.example.org/pixel", true},
+		{"https://analytics.example.io/collect", true},
+		{"https://stats.g.doubleclick.net/r", true},
+		{"https://example.net/", false},
+		{"http://localhost:8080/app.js", false},
+	}
+	for _, tt := range tests {
+		blocked, reason := b.blocked(rules, network.ResourceTypeScript, tt.url)
+		assert.Equal(t, tt.blocked, blocked, tt.url)
+		if tt.blocked {
+			assert.Equal(t, "domain", reason)
+		}
+	}
+
+	// Every request is intercepted so its domain can be checked
+	require.Len(t, b.patterns(rules), 1)
+	assert.Equal(t, "*", b.patterns(rules)[0].URLPattern)
+}
+
+func TestBlockerMissingHostsFile(t *testing.T) {
+	_, err := NewBlocker(config.BlockingConfig{Enabled: true, HostsFile: "/nonexistent/hosts"})
+	assert.Error(t, err)
+}
+
+func TestBlockerSiteOverrides(t *testing.T) {
+	b := newTestBlocker(t, config.BlockingConfig{
+		Domains: []string{"cdn.example.net", "ads.example.net"},
+		Sites: map[string]config.BlockSiteConfig{
+			"gallery.example.com": {ResourceTypes: []string{}},
+			"example.com":         {Allow: []string{"cdn.example.net"}, Domains: []string{"widgets.example.org"}},
+			"intranet.example":    {Disabled: true},
+		},
+	})
+
+	// The most specific site wins; an empty type list blocks no types
+	gallery := b.rulesFor("https://gallery.example.com/photos")
+	blocked, _ := b.blocked(gallery, network.ResourceTypeImage, "https://gallery.example.com/1.jpg")
+	assert.False(t, blocked)
+	blocked, _ = b.blocked(gallery, network.ResourceTypeScript, "https://ads.example.net/a.js")
+	assert.True(t, blocked, "the global domain list still applies")
+
+	site := b.rulesFor("https://www.example.com/")
+	blocked, _ = b.blocked(site, network.ResourceTypeImage, "https://www.example.com/logo.png")
+	assert.True(t, blocked, "default types apply when the site does not set its own")
+	blocked, _ = b.blocked(site, network.ResourceTypeScript, "https://cdn.example.net/lib.js")
+	assert.False(t, blocked, "allowed domains are exempt")
+	blocked, _ = b.blocked(site, network.ResourceTypeScript, "https://widgets.example.org/w.js")
+	assert.True(t, blocked)
+
+	other := b.rulesFor("https://other.test/")
+	blocked, _ = b.blocked(other, network.ResourceTypeScript, "https://cdn.example.net/lib.js")
+	assert.True(t, blocked)
+	blocked, _ = b.blocked(other, network.ResourceTypeScript, "https://widgets.example.org/w.js")
+	assert.False(t, blocked, "site domains only apply to their site")
+
+	assert.Nil(t, b.rulesFor("https://intranet.example/"))
+	blocked, _ = b.blocked(nil, network.ResourceTypeImage, "https://intranet.example/a.png")
+	assert.False(t, blocked)
+}
@@ -0,0 +1,307 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/chromedp/chromedp"
+	"github.com/ncecere/reader-go/internal/common/config"
+	"github.com/ncecere/reader-go/internal/common/logger"
+	commonmetrics "github.com/ncecere/reader-go/internal/common/metrics"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultConsentWait is used when ConsentConfig.Wait is 0
+	defaultConsentWait = time.Second
+	// consentPoll is how often we look for a dialog while waiting for one
+	consentPoll = 250 * time.Millisecond
+	// consentSettle gives a dialog time to close after its button was clicked
+	consentSettle = 500 * time.Millisecond
+)
+
+// ConsentRule describes a consent management platform by CSS selectors
+type ConsentRule struct {
+	Name   string `json:"name"`
+	Detect string `json:"detect"` // present while the dialog is shown
+	Accept string `json:"accept"` // button that accepts all
+	Reject string `json:"reject"` // button that rejects all, if any
+	Remove string `json:"remove"` // overlay elements removed if clicking fails
+}
+
+// consentRules are the built-in platforms; rules from the configuration are tried first
+var consentRules = []ConsentRule{
+	{
+		Name:   "onetrust",
+		Detect: "#onetrust-banner-sdk, #onetrust-pc-sdk",
+		Accept: "#onetrust-accept-btn-handler, #accept-recommended-btn-handler",
+		Reject: "#onetrust-reject-all-handler, .ot-pc-refuse-all-handler",
+		Remove: "#onetrust-consent-sdk",
+	},
+	{
+		Name:   "quantcast",
+		Detect: "#qc-cmp2-container, .qc-cmp2-container",
+		Accept: `.qc-cmp2-summary-buttons button[mode="primary"]`,
+		Reject: `.qc-cmp2-summary-buttons button[mode="secondary"]`,
+		Remove: "#qc-cmp2-container, .qc-cmp2-container",
+	},
+	{
+		Name:   "didomi",
+		Detect: "#didomi-popup, #didomi-notice",
+		Accept: "#didomi-notice-agree-button",
+		Reject: "#didomi-notice-disagree-button, .didomi-continue-without-agreeing",
+		Remove: "#didomi-host",
+	},
+	{
+		Name:   "cookiebot",
+		Detect: "#CybotCookiebotDialog",
+		Accept: "#CybotCookiebotDialogBodyLevelButtonLevelOptinAllowAll, #CybotCookiebotDialogBodyButtonAccept",
+		Reject: "#CybotCookiebotDialogBodyButtonDecline",
+		Remove: "#CybotCookiebotDialog, #CybotCookiebotDialogBodyUnderlay",
+	},
+}
+
+// consentScript finds, clicks or removes consent dialogs depending on mode
+// ("click", "check" or "remove"). Buttons matched by text alone are only clicked
+// inside containers that look like consent dialogs.
+const consentScript = `(function(cfg, mode) {
+	const words = /cookie|consent|gdpr|privacy|datenschutz|cmp/i;
+	const accept = /^(accept|accept all|accept all cookies|accept cookies|allow all|allow all cookies|agree|i agree|agree and close|got it|ok|okay|alle akzeptieren|akzeptieren|tout accepter|accepter|j'accepte|accetta|accetta tutto|aceptar|aceptar todo|aceitar|accepteren|alles accepteren|zaakceptuj)$/i;
+	const reject = /^(reject|reject all|decline|decline all|refuse|deny|necessary only|only necessary|use necessary cookies only|alle ablehnen|ablehnen|tout refuser|refuser|rifiuta|rifiuta tutto|rechazar|rechazar todo|weigeren)$/i;
+
+	const visible = (el) => {
+		if (!el) return false;
+		const style = getComputedStyle(el);
+		const rect = el.getBoundingClientRect();
+		return style.display !== 'none' && style.visibility !== 'hidden' && rect.width > 0 && rect.height > 0;
+	};
+	const all = (selector) => {
+		if (!selector) return [];
+		try { return Array.from(document.querySelectorAll(selector)); } catch (e) { return []; }
+	};
+	const shown = (selector) => all(selector).find(visible);
+	const floating = (el) => {
+		for (; el && el !== document.body; el = el.parentElement) {
+			const position = getComputedStyle(el).position;
+			if (position === 'fixed' || position === 'sticky') return el;
+		}
+		return null;
+	};
+	const consentBox = (el) => {
+		for (let node = el; node && node !== document.body; node = node.parentElement) {
+			const label = node.id + ' ' + (typeof node.className === 'string' ? node.className : '') + ' ' + (node.getAttribute('aria-label') || '');
+			if (words.test(label)) return node;
+			if (node.getAttribute('role') === 'dialog' || node.getAttribute('aria-modal') === 'true') return node;
+		}
+		const box = floating(el);
+		return box && words.test((box.innerText || '').slice(0, 3000)) ? box : null;
+	};
+	const genericDialogs = () => all('body *').filter((el) => {
+		const position = getComputedStyle(el).position;
+		if (position !== 'fixed' && position !== 'sticky') return false;
+		const label = el.id + ' ' + (typeof el.className === 'string' ? el.className : '');
+		const text = (el.innerText || '').slice(0, 3000);
+		return visible(el) && (words.test(label) || (words.test(text) && el.querySelector('button, [role=button]')));
+	});
+
+	if (mode === 'click') {
+		for (const rule of cfg.rules) {
+			if (!shown(rule.detect)) continue;
+			const button = (cfg.reject && shown(rule.reject)) || shown(rule.accept);
+			if (button) { button.click(); return {platform: rule.name, method: 'click'}; }
+			return {platform: rule.name, method: ''};
+		}
+		const texts = cfg.reject ? [reject, accept] : [accept];
+		const buttons = all('button, [role=button], a, input[type=button], input[type=submit]').filter(visible);
+		for (const pattern of texts) {
+			for (const el of buttons) {
+				const text = (el.innerText || el.value || '').trim().replace(/\s+/g, ' ');
+				if (text.length <= 40 && pattern.test(text) && consentBox(el)) {
+					el.click();
+					return {platform: 'generic', method: 'click'};
+				}
+			}
+		}
+		return {platform: '', method: ''};
+	}
+
+	if (mode === 'check') {
+		for (const rule of cfg.rules) {
+			if (shown(rule.detect)) return {platform: rule.name, method: ''};
+		}
+		return {platform: genericDialogs().length ? 'generic' : '', method: ''};
+	}
+
+	// remove
+	let removed = 0;
+	for (const rule of cfg.rules) {
+		for (const el of all(rule.remove || rule.detect)) { el.remove(); removed++; }
+	}
+	for (const el of genericDialogs()) { el.remove(); removed++; }
+	// Full-screen backdrops without content of their own
+	for (const el of all('body *')) {
+		const style = getComputedStyle(el);
+		if (style.position !== 'fixed' || !visible(el)) continue;
+		const rect = el.getBoundingClientRect();
+		if (rect.width * rect.height >= innerWidth * innerHeight * 0.8 && (el.innerText || '').trim().length < 20) {
+			el.remove();
+			removed++;
+		}
+	}
+	// Dialogs lock scrolling, which hides the rest of the page from text extraction
+	for (const el of [document.documentElement, document.body]) {
+		el.style.setProperty('overflow', 'visible', 'important');
+		el.style.setProperty('position', 'static', 'important');
+	}
+	return {platform: removed ? 'overlay' : '', method: removed ? 'remove' : ''};
+})`
+
+// consentResult is what consentScript reports
+type consentResult struct {
+	Platform string `json:"platform"`
+	Method   string `json:"method"`
+}
+
+// ConsentHandler dismisses cookie banners and consent walls after a page has loaded
+type ConsentHandler struct {
+	rules          []ConsentRule
+	reject         bool
+	removeOverlays bool
+	wait           time.Duration
+}
+
+// NewConsentHandler creates a consent handler from the configuration. It returns nil
+// if dismissal is disabled; a nil ConsentHandler does nothing.
+func NewConsentHandler(cfg config.ConsentConfig) (*ConsentHandler, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	h := &ConsentHandler{
+		removeOverlays: !cfg.KeepOverlays,
+		wait:           defaultConsentWait,
+	}
+	switch cfg.Action {
+	case "", "accept":
+	case "reject":
+		h.reject = true
+	default:
+		return nil, fmt.Errorf("unknown consent action %q (want accept or reject)", cfg.Action)
+	}
+	if cfg.Wait < 0 {
+		h.wait = 0
+	} else if cfg.Wait > 0 {
+		h.wait = time.Duration(cfg.Wait) * time.Millisecond
+	}
+
+	for _, rc := range cfg.Rules {
+		if rc.Name == "" || rc.Detect == "" {
+			return nil, fmt.Errorf("consent rules need a name and a detect selector")
+		}
+		if rc.Accept == "" && rc.Reject == "" && rc.Remove == "" {
+			return nil, fmt.Errorf("consent rule %q has no accept, reject or remove selector", rc.Name)
+		}
+		h.rules = append(h.rules, ConsentRule(rc))
+	}
+	h.rules = append(h.rules, consentRules...)
+
+	return h, nil
+}
+
+// Dismiss returns an action that waits briefly for a consent dialog, clicks its
+// accept (or reject) button and, if the dialog is still shown, removes it from the
+// page. Run it after the page has loaded and before reading its content. Failures
+// are logged rather than returned, since accepting often reloads the page.
+func (h *ConsentHandler) Dismiss() chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		if h == nil {
+			return nil
+		}
+		if err := h.dismiss(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			logger.Log.Debug("Failed to dismiss consent dialog", zap.Error(err))
+		}
+		return nil
+	})
+}
+
+// dismiss clicks or removes the consent dialog on the current page
+func (h *ConsentHandler) dismiss(ctx context.Context) error {
+	// Consent platforms often inject their dialog after the load event
+	var clicked consentResult
+	deadline := time.Now().Add(h.wait)
+	for {
+		if err := h.run(ctx, "click", &clicked); err != nil {
+			return err
+		}
+		if clicked.Platform != "" || !time.Now().Before(deadline) {
+			break
+		}
+		if err := sleep(ctx, consentPoll); err != nil {
+			return err
+		}
+	}
+	if clicked.Platform == "" {
+		return nil
+	}
+
+	if clicked.Method == "click" {
+		if err := sleep(ctx, consentSettle); err != nil {
+			return err
+		}
+		var check consentResult
+		if err := h.run(ctx, "check", &check); err != nil {
+			return err
+		}
+		if check.Platform == "" {
+			logger.Log.Debug("Dismissed consent dialog", zap.String("platform", clicked.Platform))
+			commonmetrics.ConsentDismissals.WithLabelValues(clicked.Platform, "click").Inc()
+			return nil
+		}
+	}
+
+	if !h.removeOverlays {
+		return nil
+	}
+	var removed consentResult
+	if err := h.run(ctx, "remove", &removed); err != nil {
+		return err
+	}
+	if removed.Method != "" {
+		logger.Log.Debug("Removed consent overlay", zap.String("platform", clicked.Platform))
+		commonmetrics.ConsentDismissals.WithLabelValues(clicked.Platform, "remove").Inc()
+	}
+	return nil
+}
+
+// run evaluates consentScript in the given mode
+func (h *ConsentHandler) run(ctx context.Context, mode string, result *consentResult) error {
+	cfg, err := json.Marshal(map[string]interface{}{
+		"rules":  h.rules,
+		"reject": h.reject,
+	})
+	if err != nil {
+		return err
+	}
+	expr := fmt.Sprintf("%s(%s, %q)", consentScript, cfg, mode)
+	if err := chromedp.Evaluate(expr, result).Do(ctx); err != nil {
+		return fmt.Errorf("consent %s failed: %w", mode, err)
+	}
+	return nil
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
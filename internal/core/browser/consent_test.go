@@ -0,0 +1,88 @@
+package browser_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/chromedp/chromedp"
+	"github.com/ncecere/reader-go/internal/common/config"
+	"github.com/ncecere/reader-go/internal/core/browser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewConsentHandler(t *testing.T) {
+	h, err := browser.NewConsentHandler(config.ConsentConfig{})
+	require.NoError(t, err)
+	assert.Nil(t, h, "disabled")
+
+	_, err = browser.NewConsentHandler(config.ConsentConfig{Enabled: true, Action: "ignore"})
+	assert.ErrorContains(t, err, "ignore")
+
+	_, err = browser.NewConsentHandler(config.ConsentConfig{
+		Enabled: true,
+		Rules:   []config.ConsentRuleConfig{{Name: "acme", Detect: "#acme-cmp"}},
+	})
+	assert.ErrorContains(t, err, "acme")
+
+	h, err = browser.NewConsentHandler(config.ConsentConfig{
+		Enabled: true,
+		Action:  "reject",
+		Rules:   []config.ConsentRuleConfig{{Name: "acme", Detect: "#acme-cmp", Accept: "#acme-ok"}},
+	})
+	require.NoError(t, err)
+	assert.NotNil(t, h)
+}
+
+func TestConsentHandler_Dismiss(t *testing.T) {
+	pages := map[string]string{
+		// The banner closes when its button is clicked
+		"/onetrust": `<div id="onetrust-consent-sdk"><div id="onetrust-banner-sdk" style="position:fixed;bottom:0">
+			We use cookies <button id="onetrust-accept-btn-handler"
+			onclick="document.getElementById('onetrust-consent-sdk').remove()">Accept All</button></div></div>`,
+		// A button that does nothing, so the dialog has to be removed
+		"/generic": `<div class="cookie-wall" style="position:fixed;inset:0;background:#fff">
+			We value your privacy <button>Accept all</button></div>`,
+		// A configured platform
+		"/custom": `<div id="acme-cmp" style="position:fixed;top:0">Acme consent
+			<button id="acme-ok" onclick="this.parentElement.remove()">Fine</button></div>`,
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`<!DOCTYPE html><html><body style="overflow:hidden">` + pages[r.URL.Path] +
+			`<p id="content">Article text</p></body></html>`))
+	}))
+	defer ts.Close()
+
+	pool := browser.SetupTestPool(t)
+	handler, err := browser.NewConsentHandler(config.ConsentConfig{
+		Enabled: true,
+		Wait:    -1,
+		Rules:   []config.ConsentRuleConfig{{Name: "acme", Detect: "#acme-cmp", Accept: "#acme-ok"}},
+	})
+	require.NoError(t, err)
+
+	for path := range pages {
+		t.Run(path, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+			defer cancel()
+
+			var text string
+			err := pool.Execute(ctx, func(ctx context.Context) error {
+				return chromedp.Run(ctx,
+					chromedp.Navigate(ts.URL+path),
+					chromedp.WaitReady("body", chromedp.ByQuery),
+					handler.Dismiss(),
+					chromedp.Text("body", &text, chromedp.NodeVisible, chromedp.ByQuery),
+				)
+			})
+			require.NoError(t, err)
+			assert.Contains(t, text, "Article text")
+			assert.NotContains(t, text, "Accept")
+			assert.NotContains(t, text, "Fine")
+		})
+	}
+}
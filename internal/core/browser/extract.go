@@ -2,6 +2,7 @@ package browser
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -11,8 +12,9 @@ import (
 )
 
 // ExtractTextFromPage navigates to the URL and extracts visible text from the page body.
-// It retries on failure up to 5 times.
-func ExtractTextFromPage(ctx context.Context, url string, out *string) error {
+// The prepare actions run once the page has loaded, before the text is read.
+// Loading is limited to 10 seconds per attempt. It retries on failure up to 5 times.
+func ExtractTextFromPage(ctx context.Context, url string, out *string, prepare ...chromedp.Action) error {
 	logger.Log.Info("Getting text content", zap.String("url", url))
 
 	var lastErr error
@@ -34,8 +36,14 @@ func ExtractTextFromPage(ctx context.Context, url string, out *string) error {
 					})
 				`, nil).Do(ctx)
 			}),
-			chromedp.Text("body", out, chromedp.NodeVisible, chromedp.ByQuery),
 		)
+		if err == nil {
+			// Preparing the page, e.g. scrolling it, is bounded by the request timeout
+			// rather than the load timeout
+			actions := append(append([]chromedp.Action(nil), prepare...),
+				chromedp.Text("body", out, chromedp.NodeVisible, chromedp.ByQuery))
+			err = chromedp.Run(ctx, actions...)
+		}
 		if err == nil {
 			logger.Log.Info("Successfully retrieved text",
 				zap.String("url", url),
@@ -44,6 +52,15 @@ func ExtractTextFromPage(ctx context.Context, url string, out *string) error {
 		}
 
 		lastErr = err
+		var actionErr *ActionError
+		if errors.As(err, &actionErr) {
+			// The page loaded but the caller's script does not fit it; retrying will not help
+			break
+		}
+		if isProxyError(err) {
+			// The caller fails over to the next proxy in a new tab
+			break
+		}
 		logger.Log.Warn("Retrying text extraction",
 			zap.String("url", url),
 			zap.Int("attempt", i+1),
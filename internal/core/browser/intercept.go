@@ -0,0 +1,149 @@
+package browser
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+	"github.com/ncecere/reader-go/internal/common/logger"
+	commonmetrics "github.com/ncecere/reader-go/internal/common/metrics"
+	"github.com/ncecere/reader-go/internal/core/domain"
+	"go.uber.org/zap"
+)
+
+// maxAuthAttempts bounds how often credentials are offered for one request, so wrong
+// credentials fail it instead of being retried forever
+const maxAuthAttempts = 3
+
+// interceptor answers the requests Chrome pauses for one tab
+type interceptor struct {
+	blocker   *Blocker
+	rules     *blockRules // nil blocks nothing
+	auth      *BasicAuth  // nil cancels auth challenges
+	host      string      // credentials are only sent to the page's own host
+	proxyAuth *BasicAuth  // answers the challenges of the tab's proxy
+
+	mu       sync.Mutex
+	attempts map[fetch.RequestID]int // challenges answered per request
+}
+
+// Setup returns an action that prepares the current tab for loading pageURL: it sets
+// the cookies and headers of the session configured for the page's domain merged with
+// request, answers basic auth challenges of the page and the tab's proxy and blocks
+// resources. Run it before navigating.
+//
+// Every tab has its own browser context, so cookies set here are discarded with the
+// tab and never reach other requests on the same Chrome process.
+func (p *Pool) Setup(pageURL string, request *Session) chromedp.Action {
+	session := p.sessions.For(pageURL).merge(request)
+	return chromedp.Tasks{
+		applySession(session, pageURL),
+		p.intercept(pageURL, session),
+	}
+}
+
+// intercept enables request interception in the current tab when the page needs
+// resources blocked or basic auth answered, or its proxy needs credentials
+func (p *Pool) intercept(pageURL string, session *Session) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		i := &interceptor{
+			blocker:   p.blocker,
+			rules:     p.blocker.rulesFor(pageURL),
+			host:      domain.Hostname(pageURL),
+			proxyAuth: proxyAuth(ctx),
+			attempts:  make(map[fetch.RequestID]int),
+		}
+		if session != nil {
+			i.auth = session.BasicAuth
+		}
+
+		handleAuth := i.auth != nil || i.proxyAuth != nil
+		var patterns []*fetch.RequestPattern
+		switch {
+		case handleAuth:
+			// Any request may be challenged, so all of them have to be paused
+			patterns = []*fetch.RequestPattern{{URLPattern: "*", RequestStage: fetch.RequestStageRequest}}
+		case i.rules != nil:
+			patterns = p.blocker.patterns(i.rules)
+		}
+		if len(patterns) == 0 {
+			return nil
+		}
+
+		chromedp.ListenTarget(ctx, func(ev interface{}) {
+			// Answering from within the listener would deadlock the event loop
+			switch ev := ev.(type) {
+			case *fetch.EventRequestPaused:
+				go i.paused(ctx, ev)
+			case *fetch.EventAuthRequired:
+				go i.authRequired(ctx, ev)
+			}
+		})
+		return fetch.Enable().
+			WithPatterns(patterns).
+			WithHandleAuthRequests(handleAuth).
+			Do(ctx)
+	})
+}
+
+// paused fails or continues a paused request
+func (i *interceptor) paused(ctx context.Context, ev *fetch.EventRequestPaused) {
+	execCtx := cdp.WithExecutor(ctx, chromedp.FromContext(ctx).Target)
+
+	var err error
+	if block, reason := i.blocker.blocked(i.rules, ev.ResourceType, ev.Request.URL); block {
+		commonmetrics.BrowserBlockedRequests.WithLabelValues(strings.ToLower(ev.ResourceType.String()), reason).Inc()
+		err = fetch.FailRequest(ev.RequestID, network.ErrorReasonBlockedByClient).Do(execCtx)
+	} else {
+		err = fetch.ContinueRequest(ev.RequestID).Do(execCtx)
+	}
+	if err != nil && ctx.Err() == nil {
+		logger.Log.Debug("Failed to answer intercepted request",
+			zap.String("url", ev.Request.URL),
+			zap.Error(err))
+	}
+}
+
+// authRequired answers a proxy challenge with the proxy's credentials and a server
+// challenge with the session's credentials if it comes from the page's host. Other
+// challenges are cancelled.
+func (i *interceptor) authRequired(ctx context.Context, ev *fetch.EventAuthRequired) {
+	execCtx := cdp.WithExecutor(ctx, chromedp.FromContext(ctx).Target)
+
+	var credentials *BasicAuth
+	if challenge := ev.AuthChallenge; challenge != nil {
+		switch {
+		case challenge.Source == fetch.AuthChallengeSourceProxy:
+			credentials = i.proxyAuth
+		case domain.Hostname(challenge.Origin) == i.host:
+			credentials = i.auth
+		}
+	}
+	if credentials != nil {
+		i.mu.Lock()
+		i.attempts[ev.RequestID]++
+		if i.attempts[ev.RequestID] > maxAuthAttempts {
+			credentials = nil
+		}
+		i.mu.Unlock()
+	}
+
+	response := &fetch.AuthChallengeResponse{Response: fetch.AuthChallengeResponseResponseCancelAuth}
+	if credentials != nil {
+		response = &fetch.AuthChallengeResponse{
+			Response: fetch.AuthChallengeResponseResponseProvideCredentials,
+			Username: credentials.Username,
+			Password: credentials.Password,
+		}
+	}
+
+	if err := fetch.ContinueWithAuth(ev.RequestID, response).Do(execCtx); err != nil && ctx.Err() == nil {
+		logger.Log.Debug("Failed to answer auth challenge",
+			zap.String("url", ev.Request.URL),
+			zap.Error(err))
+	}
+}
@@ -4,15 +4,46 @@ import (
 	"fmt"
 
 	"github.com/chromedp/chromedp"
+	"github.com/ncecere/reader-go/internal/common/config"
 )
 
 // BrowserOptions contains configuration for the browser service
 type BrowserOptions struct {
-	PoolSize    int
+	PoolSize    int // Maximum concurrent tabs
+	Processes   int // Chrome processes hosting the tabs (0 = one per 8 tabs)
 	ChromePath  string
 	Timeout     int    // in seconds
-	MaxMemoryMB int    // Maximum memory per instance in MB
+	MaxMemoryMB int    // V8 heap limit per renderer in MB
 	UserAgent   string // Custom user agent
+
+	// Processes are recycled after opening MaxUses tabs or after MaxAge minutes, and
+	// probed every HealthCheckInterval seconds. 0 uses the default, -1 disables.
+	MaxUses             int
+	MaxAge              int
+	HealthCheckInterval int
+
+	// Processes whose RSS, including renderers and other children, exceeds
+	// MemoryLimitMB are recycled. Sampled every health check; 0 uses the default, -1 disables.
+	MemoryLimitMB int
+
+	// Requests wait for a tab in a FIFO queue of at most MaxQueue entries
+	// (0 uses the default, -1 is unbounded) for up to QueueTimeout seconds (0 uses Timeout)
+	MaxQueue     int
+	QueueTimeout int
+
+	// Requests that ask for auto-scrolling scroll at most MaxScrolls times for up to
+	// ScrollTimeout seconds (0 uses the defaults of 20 and 10)
+	MaxScrolls    int
+	ScrollTimeout int
+
+	// Blocking selects resources that pages load without
+	Blocking config.BlockingConfig
+	// Consent dismisses cookie banners before page content is read
+	Consent config.ConsentConfig
+	// Sessions maps domains to the cookies, headers and basic auth their pages are fetched with
+	Sessions map[string]config.SessionConfig
+	// Proxy selects the proxies tabs connect through
+	Proxy config.ProxyConfig
 }
 
 // DefaultOptions returns default browser options
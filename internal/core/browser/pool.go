@@ -9,40 +9,113 @@ import (
 
 	"github.com/chromedp/chromedp"
 	"github.com/ncecere/reader-go/internal/common/logger"
+	commonmetrics "github.com/ncecere/reader-go/internal/common/metrics"
 	"github.com/ncecere/reader-go/internal/core/metrics"
+	"github.com/ncecere/reader-go/internal/core/proxy"
 	"go.uber.org/zap"
 )
 
-// Pool manages a pool of browser instances
+const (
+	// defaultHealthCheckInterval is used when BrowserOptions.HealthCheckInterval is 0
+	defaultHealthCheckInterval = 30 * time.Second
+	// healthCheckTimeout bounds a single health probe
+	healthCheckTimeout = 5 * time.Second
+	// defaultMaxUses is used when BrowserOptions.MaxUses is 0
+	defaultMaxUses = 100
+	// defaultMaxAge is used when BrowserOptions.MaxAge is 0
+	defaultMaxAge = 30 * time.Minute
+	// defaultTabsPerProcess sizes the number of processes when BrowserOptions.Processes is 0
+	defaultTabsPerProcess = 8
+	// defaultMemoryLimitMB is used when BrowserOptions.MemoryLimitMB is 0
+	defaultMemoryLimitMB = 2048
+)
+
+// Pool runs each request in its own incognito tab. Tabs are spread over a few Chrome
+// processes; PoolSize bounds the number of concurrent tabs.
 type Pool struct {
 	instances []*Instance
 	opts      *BrowserOptions
 	mu        sync.RWMutex // Changed to RWMutex for better concurrency
-	ready     chan *Instance
 	metrics   *metrics.Metrics
+	blocker   *Blocker
+	consent   *ConsentHandler
+	sessions  *Sessions
+	proxies   *proxy.Manager
+
+	// active counts tab slots in use; queue holds requests waiting for one, oldest first
+	active int
+	queue  []chan struct{}
+	closed bool
+
+	// relaunching is the launch in progress after every instance was lost
+	relaunching *pendingLaunch
+
+	// launch, openTab, probe, usage and heap are swapped out in tests
+	launch  func() (*Instance, error)
+	openTab func(*Instance, *proxy.Proxy) (*Tab, error)
+	probe   func(*Instance) error
+	usage   func(pid int) (processUsage, error)
+	heap    func(*Tab) (float64, error)
+
+	stop chan struct{}
+	wg   sync.WaitGroup
 }
 
-// Instance represents a browser instance
+// Instance is a Chrome process hosting the tabs of concurrent requests
 type Instance struct {
-	ctx    context.Context
-	cancel context.CancelFunc
-	inUse  bool
+	ctx       context.Context
+	cancel    context.CancelFunc
+	createdAt time.Time
+	uses      int  // tabs opened over the process lifetime
+	tabs      int  // tabs currently open
+	retiring  bool // no new tabs; closed once its open tabs finish
+
+	// pid is the Chrome browser process; cpuSeconds and sampledAt hold the last sample
+	pid        int
+	cpuSeconds float64
+	sampledAt  time.Time
 }
 
 // NewPool creates a new browser pool
 func NewPool(opts *BrowserOptions, metrics *metrics.Metrics) (*Pool, error) {
+	blocker, err := NewBlocker(opts.Blocking)
+	if err != nil {
+		return nil, fmt.Errorf("invalid resource blocking configuration: %w", err)
+	}
+	consent, err := NewConsentHandler(opts.Consent)
+	if err != nil {
+		return nil, fmt.Errorf("invalid consent configuration: %w", err)
+	}
+	sessions, err := NewSessions(opts.Sessions)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session configuration: %w", err)
+	}
+	proxies, err := proxy.New(opts.Proxy, "browser")
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy configuration: %w", err)
+	}
+
 	pool := &Pool{
 		opts:      opts,
-		instances: make([]*Instance, 0, opts.PoolSize), // Initialize with zero length
-		ready:     make(chan *Instance, opts.PoolSize),
+		instances: make([]*Instance, 0, opts.Processes), // Initialize with zero length
 		metrics:   metrics,
+		blocker:   blocker,
+		consent:   consent,
+		sessions:  sessions,
+		proxies:   proxies,
+		stop:      make(chan struct{}),
 	}
+	pool.launch = pool.createInstance
+	pool.openTab = pool.createTab
+	pool.probe = probeInstance
+	pool.usage = processTreeUsage
+	pool.heap = jsHeapUsed
 
 	logger.Log.Info("Initializing Chrome instances")
 
 	// Initialize instances
-	for i := 0; i < opts.PoolSize; i++ {
-		instance, err := pool.createInstance()
+	for i := 0; i < pool.processes(); i++ {
+		instance, err := pool.launch()
 		if err != nil {
 			// Clean up any instances that were created
 			for _, inst := range pool.instances {
@@ -53,7 +126,6 @@ func NewPool(opts *BrowserOptions, metrics *metrics.Metrics) (*Pool, error) {
 			return nil, fmt.Errorf("failed to create instance %d: %w", i, err)
 		}
 		pool.instances = append(pool.instances, instance)
-		pool.ready <- instance
 	}
 
 	// Warm up instances
@@ -61,21 +133,38 @@ func NewPool(opts *BrowserOptions, metrics *metrics.Metrics) (*Pool, error) {
 		logger.Log.Warn("Failed to warm up browser instances", zap.Error(err))
 	}
 
+	pool.startHealthChecks()
+
 	logger.Log.Info("Browser pool initialized successfully",
-		zap.Int("pool_size", opts.PoolSize))
+		zap.Int("pool_size", opts.PoolSize),
+		zap.Int("processes", pool.processes()))
 
 	return pool, nil
 }
 
-// Execute runs a function with a browser instance
+// Execute runs a function in a fresh incognito tab that is closed afterwards
 func (p *Pool) Execute(ctx context.Context, fn func(context.Context) error) error {
-	instance, err := p.getInstance()
+	return p.execute(ctx, nil, fn)
+}
+
+// ExecuteURL is Execute for a function that loads pageURL. The tab connects through
+// the proxy chosen for pageURL; if the proxy cannot be reached, fn runs again in a new
+// tab through the next proxy of the rotation.
+func (p *Pool) ExecuteURL(ctx context.Context, pageURL string, fn func(context.Context) error) error {
+	return p.proxies.Do(pageURL, func(px *proxy.Proxy) error {
+		return p.execute(ctx, px, fn)
+	}, isProxyError)
+}
+
+// execute runs fn in a fresh tab connecting through px, or directly if px is nil
+func (p *Pool) execute(ctx context.Context, px *proxy.Proxy, fn func(context.Context) error) error {
+	tab, err := p.getTab(ctx, px)
 	if err != nil {
 		return fmt.Errorf("failed to get browser instance: %w", err)
 	}
 
 	// Create a new context with timeout from the pool options
-	timeoutCtx, cancel := context.WithTimeout(instance.ctx, time.Duration(p.opts.Timeout)*time.Second)
+	timeoutCtx, cancel := context.WithTimeout(tab.ctx, time.Duration(p.opts.Timeout)*time.Second)
 	defer cancel()
 
 	// Create a merged context that will be canceled if either the parent context
@@ -97,80 +186,228 @@ func (p *Pool) Execute(ctx context.Context, fn func(context.Context) error) erro
 	}()
 
 	// Execute function with merged context
-	err = fn(mergedCtx)
+	err = fn(withTabProxy(mergedCtx, px))
 	close(done)
 
-	// Mark instance as available
+	p.observeHeap(tab)
+	p.closeTab(tab)
+	return err
+}
+
+// DismissConsent returns an action that dismisses cookie banners on the loaded page.
+// Run it before reading the page content.
+func (p *Pool) DismissConsent() chromedp.Action {
+	return p.consent.Dismiss()
+}
+
+// recycleReason returns why an instance should be replaced, or "". The caller must hold p.mu.
+func (p *Pool) recycleReason(instance *Instance) string {
+	switch {
+	case instance.retiring:
+		return ""
+	case instance.ctx.Err() != nil:
+		return "crashed"
+	case p.maxUses() > 0 && instance.uses >= p.maxUses():
+		return "max_uses"
+	case p.maxAge() > 0 && time.Since(instance.createdAt) >= p.maxAge():
+		return "max_age"
+	}
+	return ""
+}
+
+// recycleAsync replaces an instance in the background so the caller is not held up
+func (p *Pool) recycleAsync(instance *Instance, reason string) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
-	instance.inUse = false
-
-	// Handle context cancellation
-	if err != nil {
-		if err == context.Canceled || err == context.DeadlineExceeded {
-			logger.Log.Info("Browser instance timed out, recreating...")
-			newInstance, createErr := p.createInstance()
-			if createErr == nil {
-				for i, inst := range p.instances {
-					if inst == instance {
-						instance.cancel() // Cancel old instance
-						p.instances[i] = newInstance
-						break
-					}
-				}
-			}
-		}
-		return err
+	if p.closed {
+		return
 	}
 
-	return nil
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		p.recycle(instance, reason)
+	}()
 }
 
-// updateMetrics updates pool and memory metrics
-func (p *Pool) updateMetrics() {
-	activeCount := int32(0)
-	for _, instance := range p.instances {
-		if instance.inUse {
-			activeCount++
-		}
+// recycle replaces an instance with a freshly launched one. The old instance stops
+// taking new tabs and is closed once its open tabs finish. If the launch fails the
+// instance is dropped and the next health check launches a replacement.
+func (p *Pool) recycle(instance *Instance, reason string) {
+	p.mu.Lock()
+	if instance.retiring || p.closed {
+		p.mu.Unlock()
+		return
 	}
-	p.metrics.UpdatePoolMetrics(int32(p.opts.PoolSize), activeCount)
+	instance.retiring = true
+	p.mu.Unlock()
 
-	// Estimate memory usage (rough estimate: 100MB base + MaxMemoryMB per active instance)
-	currentMemoryMB := uint64(100 + (p.opts.MaxMemoryMB * int(activeCount)))
-	p.metrics.UpdateMemoryUsage(currentMemoryMB)
-}
+	logger.Log.Info("Recycling browser instance",
+		zap.String("reason", reason),
+		zap.Int("uses", instance.uses),
+		zap.Duration("age", time.Since(instance.createdAt)))
+	commonmetrics.BrowserRecycles.WithLabelValues(reason).Inc()
+
+	newInstance, err := p.launch()
 
-// getInstance gets an available browser instance
-func (p *Pool) getInstance() (*Instance, error) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
-	// Update metrics
+	if p.closed {
+		if err == nil {
+			newInstance.cancel()
+		}
+		return
+	}
+
+	for i, inst := range p.instances {
+		if inst != instance {
+			continue
+		}
+		if err != nil {
+			logger.Log.Error("Failed to replace browser instance", zap.Error(err))
+			p.instances = append(p.instances[:i], p.instances[i+1:]...)
+		} else {
+			p.instances[i] = newInstance
+		}
+		break
+	}
+	if instance.tabs == 0 {
+		instance.cancel()
+	}
 	p.updateMetrics()
+}
+
+// processes returns how many Chrome processes host the pool's tabs
+func (p *Pool) processes() int {
+	if p.opts.Processes > 0 {
+		return p.opts.Processes
+	}
+	return (p.opts.PoolSize + defaultTabsPerProcess - 1) / defaultTabsPerProcess
+}
+
+// maxUses returns how many tabs an instance opens before it is recycled; 0 is unlimited
+func (p *Pool) maxUses() int {
+	switch {
+	case p.opts.MaxUses < 0:
+		return 0
+	case p.opts.MaxUses == 0:
+		return defaultMaxUses
+	}
+	return p.opts.MaxUses
+}
+
+// maxAge returns how long an instance lives before it is recycled; 0 is unlimited
+func (p *Pool) maxAge() time.Duration {
+	switch {
+	case p.opts.MaxAge < 0:
+		return 0
+	case p.opts.MaxAge == 0:
+		return defaultMaxAge
+	}
+	return time.Duration(p.opts.MaxAge) * time.Minute
+}
 
-	// Find available instance
+// memoryLimit returns the RSS above which an instance is recycled; 0 is unlimited
+func (p *Pool) memoryLimit() uint64 {
+	switch {
+	case p.opts.MemoryLimitMB < 0:
+		return 0
+	case p.opts.MemoryLimitMB == 0:
+		return defaultMemoryLimitMB << 20
+	}
+	return uint64(p.opts.MemoryLimitMB) << 20
+}
+
+// startHealthChecks periodically probes and samples instances until the pool is closed
+func (p *Pool) startHealthChecks() {
+	interval := defaultHealthCheckInterval
+	if p.opts.HealthCheckInterval < 0 {
+		return
+	} else if p.opts.HealthCheckInterval > 0 {
+		interval = time.Duration(p.opts.HealthCheckInterval) * time.Second
+	}
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-p.stop:
+				return
+			case <-ticker.C:
+				p.checkHealth()
+				p.sampleResources()
+			}
+		}
+	}()
+}
+
+// checkHealth probes every instance, recycles those that fail or are too old and
+// launches instances lost to failed replacements
+func (p *Pool) checkHealth() {
+	p.mu.Lock()
+	var live []*Instance
 	for _, instance := range p.instances {
-		if !instance.inUse {
-			instance.inUse = true
-			p.updateMetrics() // Update metrics after instance allocation
-			return instance, nil
+		if !instance.retiring {
+			live = append(live, instance)
+		}
+	}
+	missing := p.processes() - len(p.instances)
+	p.mu.Unlock()
+
+	for _, instance := range live {
+		if err := p.probe(instance); err != nil {
+			logger.Log.Warn("Browser instance failed health check", zap.Error(err))
+			p.recycle(instance, "unhealthy")
+		} else if p.maxAge() > 0 && time.Since(instance.createdAt) >= p.maxAge() {
+			p.recycle(instance, "max_age")
 		}
 	}
 
-	// No available instances, create new one if possible
-	if len(p.instances) < p.opts.PoolSize*2 {
-		instance, err := p.createInstance()
+	for i := 0; i < missing; i++ {
+		instance, err := p.launch()
 		if err != nil {
-			return nil, err
+			logger.Log.Error("Failed to launch browser instance", zap.Error(err))
+			return
+		}
+
+		p.mu.Lock()
+		if p.closed {
+			p.mu.Unlock()
+			instance.cancel()
+			return
 		}
-		instance.inUse = true
 		p.instances = append(p.instances, instance)
-		p.updateMetrics() // Update metrics after instance creation
-		return instance, nil
+		p.updateMetrics()
+		p.mu.Unlock()
 	}
+}
+
+// probeInstance checks that the browser still evaluates JavaScript in its initial tab
+func probeInstance(instance *Instance) error {
+	if err := instance.ctx.Err(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(instance.ctx, healthCheckTimeout)
+	defer cancel()
 
-	return nil, fmt.Errorf("no available browser instances")
+	var result int
+	if err := chromedp.Run(ctx, chromedp.Evaluate(`1 + 1`, &result)); err != nil {
+		return fmt.Errorf("health probe failed: %w", err)
+	}
+	return nil
+}
+
+// updateMetrics updates pool metrics. The caller must hold p.mu.
+func (p *Pool) updateMetrics() {
+	p.metrics.UpdatePoolMetrics(int32(p.opts.PoolSize), int32(p.active))
+	commonmetrics.BrowserTabs.Set(float64(p.active))
+	commonmetrics.BrowserProcesses.Set(float64(len(p.instances)))
 }
 
 // Warmup pre-initializes browser instances by navigating to about:blank
@@ -193,10 +430,10 @@ func (p *Pool) createInstance() (*Instance, error) {
 	opts = append(opts, chromedp.WindowSize(1920, 1080))
 
 	// Create context with custom allocator options
-	allocCtx, _ := chromedp.NewExecAllocator(context.Background(), opts...)
+	allocCtx, allocCancel := chromedp.NewExecAllocator(context.Background(), opts...)
 
 	// Create browser context with selective logging
-	ctx, _ := chromedp.NewContext(allocCtx,
+	ctx, ctxCancel := chromedp.NewContext(allocCtx,
 		chromedp.WithLogf(func(format string, args ...interface{}) {
 			// Only log non-cookie related messages at debug level
 			if !strings.Contains(format, "cookiePart") {
@@ -213,24 +450,42 @@ func (p *Pool) createInstance() (*Instance, error) {
 		}),
 	)
 
-	// Create base context with long timeout
-	baseCtx, baseCancel := context.WithTimeout(ctx, 5*time.Minute)
+	// The browser lives until it is recycled; requests get their own timeouts in Execute.
+	// Cancelling twice would block on the allocator, so it only happens once.
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			ctxCancel()
+			allocCancel()
+		})
+	}
 
 	// Start the browser
-	if err := chromedp.Run(baseCtx); err != nil {
-		baseCancel()
+	if err := chromedp.Run(ctx); err != nil {
+		cancel()
 		return nil, fmt.Errorf("failed to start browser: %w", err)
 	}
 
-	return &Instance{
-		ctx:    baseCtx,
-		cancel: baseCancel,
-		inUse:  false,
-	}, nil
+	instance := &Instance{
+		ctx:       ctx,
+		cancel:    cancel,
+		createdAt: time.Now(),
+	}
+	if process := chromedp.FromContext(ctx).Browser.Process(); process != nil {
+		instance.pid = process.Pid
+	}
+	return instance, nil
 }
 
-// Close closes all browser instances
+// Close stops health checks and closes all browser instances
 func (p *Pool) Close() {
+	p.mu.Lock()
+	p.closed = true
+	p.mu.Unlock()
+
+	close(p.stop)
+	p.wg.Wait()
+
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
@@ -239,4 +494,5 @@ func (p *Pool) Close() {
 			instance.cancel()
 		}
 	}
+	p.proxies.Close()
 }
@@ -0,0 +1,267 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	commonmetrics "github.com/ncecere/reader-go/internal/common/metrics"
+	"github.com/ncecere/reader-go/internal/core/metrics"
+	"github.com/ncecere/reader-go/internal/core/proxy"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLauncher hands out instances and tabs backed by plain contexts instead of Chrome
+type fakeLauncher struct {
+	mu       sync.Mutex
+	launched int
+	fail     int // number of upcoming launches that fail
+	tabs     int
+	failTabs bool
+}
+
+func (l *fakeLauncher) launch() (*Instance, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.fail > 0 {
+		l.fail--
+		return nil, errors.New("launch failed")
+	}
+	l.launched++
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Instance{ctx: ctx, cancel: cancel, createdAt: time.Now()}, nil
+}
+
+func (l *fakeLauncher) openTab(instance *Instance, _ *proxy.Proxy) (*Tab, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.failTabs {
+		return nil, errors.New("target crashed")
+	}
+	l.tabs++
+	ctx, cancel := context.WithCancel(instance.ctx)
+	return &Tab{ctx: ctx, cancel: cancel, instance: instance}, nil
+}
+
+func (l *fakeLauncher) count() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.launched
+}
+
+// newFakePool creates a pool of fake instances with health checks disabled
+func newFakePool(t *testing.T, opts *BrowserOptions) (*Pool, *fakeLauncher) {
+	cleanup := setupTestLogger(t)
+	t.Cleanup(cleanup)
+
+	if opts.PoolSize == 0 {
+		opts.PoolSize = 2
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = 5
+	}
+	if opts.HealthCheckInterval == 0 {
+		opts.HealthCheckInterval = -1
+	}
+
+	launcher := &fakeLauncher{}
+	p := &Pool{
+		opts:    opts,
+		metrics: metrics.New(),
+		stop:    make(chan struct{}),
+		launch:  launcher.launch,
+		openTab: launcher.openTab,
+		probe:   func(*Instance) error { return nil },
+		usage:   func(int) (processUsage, error) { return processUsage{}, errors.New("no process") },
+		heap:    func(*Tab) (float64, error) { return 0, errors.New("no page") },
+	}
+	for i := 0; i < p.processes(); i++ {
+		instance, err := p.launch()
+		require.NoError(t, err)
+		p.instances = append(p.instances, instance)
+	}
+	t.Cleanup(p.Close)
+	return p, launcher
+}
+
+// instances returns a snapshot of the pool's instances
+func instances(p *Pool) []*Instance {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]*Instance(nil), p.instances...)
+}
+
+func recycles(reason string) float64 {
+	return testutil.ToFloat64(commonmetrics.BrowserRecycles.WithLabelValues(reason))
+}
+
+func TestPoolSpreadsTabsOverProcesses(t *testing.T) {
+	p, launcher := newFakePool(t, &BrowserOptions{PoolSize: 4, Processes: 2})
+
+	var tabs []*Tab
+	for i := 0; i < 4; i++ {
+		tab, err := p.getTab(context.Background(), nil)
+		require.NoError(t, err)
+		tabs = append(tabs, tab)
+	}
+
+	assert.Equal(t, 2, launcher.count())
+	for _, instance := range instances(p) {
+		assert.Equal(t, 2, instance.tabs)
+	}
+
+	for _, tab := range tabs {
+		p.closeTab(tab)
+		assert.Error(t, tab.ctx.Err(), "closing a tab disposes its context")
+	}
+	assert.Zero(t, p.active)
+}
+
+func TestPoolRunsEachRequestInItsOwnTab(t *testing.T) {
+	p, launcher := newFakePool(t, &BrowserOptions{Processes: 1, MaxUses: -1})
+
+	var seen []context.Context
+	for i := 0; i < 3; i++ {
+		err := p.Execute(context.Background(), func(ctx context.Context) error {
+			seen = append(seen, ctx)
+			return nil
+		})
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, 3, launcher.tabs)
+	assert.Equal(t, 1, launcher.count(), "tabs share the process")
+	for _, ctx := range seen {
+		assert.Error(t, ctx.Err())
+	}
+}
+
+func TestPoolRecyclesAfterMaxUses(t *testing.T) {
+	p, launcher := newFakePool(t, &BrowserOptions{Processes: 1, MaxUses: 2})
+	before := recycles("max_uses")
+	first := instances(p)[0]
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, p.Execute(context.Background(), func(context.Context) error { return nil }))
+	}
+
+	require.Eventually(t, func() bool { return launcher.count() == 2 }, time.Second, time.Millisecond)
+	p.wg.Wait()
+	assert.Equal(t, before+1, recycles("max_uses"))
+	assert.NotSame(t, first, instances(p)[0])
+	assert.Error(t, first.ctx.Err(), "the old process is closed")
+}
+
+func TestPoolDrainsRetiringProcess(t *testing.T) {
+	p, _ := newFakePool(t, &BrowserOptions{Processes: 1, MaxUses: 1})
+	first := instances(p)[0]
+
+	busy, err := p.getTab(context.Background(), nil)
+	require.NoError(t, err)
+	done, err := p.getTab(context.Background(), nil)
+	require.NoError(t, err)
+
+	p.closeTab(done)
+	p.wg.Wait()
+	assert.NotSame(t, first, instances(p)[0])
+	assert.NoError(t, first.ctx.Err(), "a retiring process keeps serving its open tabs")
+	assert.NoError(t, busy.ctx.Err())
+
+	p.closeTab(busy)
+	assert.Error(t, first.ctx.Err(), "the process closes with its last tab")
+}
+
+func TestPoolRecyclesCrashedProcess(t *testing.T) {
+	p, launcher := newFakePool(t, &BrowserOptions{Processes: 1, MaxUses: -1, MaxAge: -1})
+	before := recycles("crashed")
+
+	err := p.Execute(context.Background(), func(context.Context) error {
+		instances(p)[0].cancel() // the browser process died
+		return errors.New("websocket closed")
+	})
+	assert.Error(t, err)
+
+	p.wg.Wait()
+	assert.Equal(t, before+1, recycles("crashed"))
+	assert.Equal(t, 2, launcher.count())
+
+	err = p.Execute(context.Background(), func(context.Context) error { return context.DeadlineExceeded })
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	p.wg.Wait()
+	assert.Equal(t, 2, launcher.count(), "a timed out tab is closed without recycling its process")
+}
+
+func TestPoolRecyclesProcessThatCannotOpenTabs(t *testing.T) {
+	p, launcher := newFakePool(t, &BrowserOptions{Processes: 1})
+	launcher.failTabs = true
+
+	err := p.Execute(context.Background(), func(context.Context) error { return nil })
+	assert.Error(t, err)
+	p.wg.Wait()
+	assert.Equal(t, 2, launcher.count())
+	assert.Zero(t, p.active, "the slot is released")
+}
+
+func TestPoolHealthCheck(t *testing.T) {
+	p, launcher := newFakePool(t, &BrowserOptions{PoolSize: 8, Processes: 3, MaxAge: 1})
+	all := instances(p)
+	sick, old := all[0], all[1]
+	old.createdAt = time.Now().Add(-2 * time.Minute)
+	p.probe = func(instance *Instance) error {
+		if instance == sick {
+			return errors.New("renderer crashed")
+		}
+		return nil
+	}
+	unhealthy, aged := recycles("unhealthy"), recycles("max_age")
+
+	p.checkHealth()
+
+	assert.Equal(t, unhealthy+1, recycles("unhealthy"))
+	assert.Equal(t, aged+1, recycles("max_age"))
+	assert.Equal(t, 5, launcher.count())
+	assert.NotContains(t, instances(p), sick)
+	assert.NotContains(t, instances(p), old)
+	assert.Contains(t, instances(p), all[2])
+}
+
+func TestPoolHealthCheckReplacesLostProcesses(t *testing.T) {
+	p, launcher := newFakePool(t, &BrowserOptions{Processes: 2})
+	launcher.fail = 1
+
+	p.recycle(instances(p)[0], "crashed")
+	assert.Len(t, instances(p), 1, "a failed replacement drops the process")
+
+	p.checkHealth()
+	assert.Len(t, instances(p), 2)
+	assert.Equal(t, 3, launcher.count())
+}
+
+func TestPoolRelaunchesOnceAfterLosingEveryProcess(t *testing.T) {
+	p, launcher := newFakePool(t, &BrowserOptions{PoolSize: 8, Processes: 2})
+	for _, instance := range instances(p) {
+		instance.cancel()
+	}
+	launch := p.launch
+	p.launch = func() (*Instance, error) {
+		time.Sleep(20 * time.Millisecond)
+		return launch()
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, p.Execute(context.Background(), func(context.Context) error { return nil }))
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 3, launcher.count(), "a burst waits for a single launch")
+	assert.Len(t, instances(p), 2, "the new process takes a lost one's place")
+}
@@ -0,0 +1,106 @@
+package browser
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// clockTicks is USER_HZ, the unit of CPU times in /proc/[pid]/stat. It is 100 on
+// every mainstream Linux platform and cannot be read without cgo.
+const clockTicks = 100
+
+// processUsage is the resource usage of a process and all of its descendants
+type processUsage struct {
+	RSSBytes   uint64
+	CPUSeconds float64
+	Processes  int
+}
+
+// procStat holds the fields of /proc/[pid]/stat that we use
+type procStat struct {
+	pid, ppid int
+	cpuTicks  uint64
+	rssPages  uint64
+}
+
+// processTreeUsage sums RSS and CPU time over pid and its descendants. Chrome runs
+// renderers, the GPU process and utilities as children of the browser process, so
+// the tree is what a browser actually costs. It only works where /proc is available.
+func processTreeUsage(pid int) (processUsage, error) {
+	paths, err := filepath.Glob("/proc/[0-9]*/stat")
+	if err != nil || len(paths) == 0 {
+		return processUsage{}, fmt.Errorf("process statistics unavailable: no /proc")
+	}
+
+	stats := make(map[int]procStat, len(paths))
+	children := make(map[int][]int)
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			// The process exited while we were scanning
+			continue
+		}
+		stat, err := parseProcStat(string(data))
+		if err != nil {
+			continue
+		}
+		stats[stat.pid] = stat
+		children[stat.ppid] = append(children[stat.ppid], stat.pid)
+	}
+
+	if _, ok := stats[pid]; !ok {
+		return processUsage{}, fmt.Errorf("process %d not found", pid)
+	}
+
+	var usage processUsage
+	pageSize := uint64(os.Getpagesize())
+	queue := []int{pid}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		stat := stats[current]
+		usage.RSSBytes += stat.rssPages * pageSize
+		usage.CPUSeconds += float64(stat.cpuTicks) / clockTicks
+		usage.Processes++
+		queue = append(queue, children[current]...)
+	}
+	return usage, nil
+}
+
+// parseProcStat parses a /proc/[pid]/stat line. The command name is wrapped in
+// parentheses and may itself contain spaces and parentheses.
+func parseProcStat(line string) (procStat, error) {
+	open := strings.IndexByte(line, '(')
+	end := strings.LastIndexByte(line, ')')
+	if open < 0 || end < open {
+		return procStat{}, fmt.Errorf("malformed stat line")
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(line[:open]))
+	if err != nil {
+		return procStat{}, fmt.Errorf("malformed pid: %w", err)
+	}
+
+	// Fields after the command start at field 3 (state)
+	fields := strings.Fields(line[end+1:])
+	if len(fields) < 22 {
+		return procStat{}, fmt.Errorf("stat line has %d fields", len(fields))
+	}
+
+	ppid, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return procStat{}, fmt.Errorf("malformed ppid: %w", err)
+	}
+	utime, _ := strconv.ParseUint(fields[11], 10, 64)
+	stime, _ := strconv.ParseUint(fields[12], 10, 64)
+	rss, _ := strconv.ParseInt(fields[21], 10, 64)
+	if rss < 0 {
+		rss = 0
+	}
+
+	return procStat{pid: pid, ppid: ppid, cpuTicks: utime + stime, rssPages: uint64(rss)}, nil
+}
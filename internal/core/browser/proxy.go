@@ -0,0 +1,57 @@
+package browser
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ncecere/reader-go/internal/core/proxy"
+)
+
+// proxyErrors are the Chrome network errors that mean the proxy failed rather than the page
+var proxyErrors = []string{
+	"ERR_PROXY_CONNECTION_FAILED",
+	"ERR_TUNNEL_CONNECTION_FAILED",
+	"ERR_SOCKS_CONNECTION_FAILED",
+	"ERR_PROXY_AUTH_UNSUPPORTED",
+	"ERR_UNEXPECTED_PROXY_AUTH",
+	"ERR_PROXY_CERTIFICATE_INVALID",
+	"ERR_NO_SUPPORTED_PROXIES",
+}
+
+// isProxyError reports whether a page failed to load because its proxy failed
+func isProxyError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	for _, code := range proxyErrors {
+		if strings.Contains(msg, code) {
+			return true
+		}
+	}
+	return false
+}
+
+type tabProxyKey struct{}
+
+// withTabProxy records the proxy the tab running under ctx connects through
+func withTabProxy(ctx context.Context, px *proxy.Proxy) context.Context {
+	if px == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, tabProxyKey{}, px)
+}
+
+// proxyAuth returns the credentials Chrome has to answer proxy challenges with for the
+// tab running under ctx, or nil. SOCKS5 proxies authenticate through their bridge.
+func proxyAuth(ctx context.Context) *BasicAuth {
+	px, _ := ctx.Value(tabProxyKey{}).(*proxy.Proxy)
+	if px == nil || px.SOCKS() {
+		return nil
+	}
+	username, password, ok := px.Credentials()
+	if !ok {
+		return nil
+	}
+	return &BasicAuth{Username: username, Password: password}
+}
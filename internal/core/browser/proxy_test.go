@@ -0,0 +1,72 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/chromedp/chromedp"
+	"github.com/ncecere/reader-go/internal/common/config"
+	"github.com/ncecere/reader-go/internal/core/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsProxyError(t *testing.T) {
+	assert.True(t, isProxyError(errors.New("page load error net::ERR_PROXY_CONNECTION_FAILED")))
+	assert.True(t, isProxyError(fmt.Errorf("text extraction failed: %w", errors.New("page load error net::ERR_TUNNEL_CONNECTION_FAILED"))))
+	assert.False(t, isProxyError(errors.New("page load error net::ERR_NAME_NOT_RESOLVED")))
+	assert.False(t, isProxyError(nil))
+}
+
+func TestPool_ExecuteURLProxy(t *testing.T) {
+	cleanup := setupTestLogger(t)
+	t.Cleanup(cleanup)
+
+	// A forward proxy that wants credentials and answers for every page itself
+	forward := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Proxy-Authorization") == "" {
+			w.Header().Set("Proxy-Authenticate", `Basic realm="corp"`)
+			w.WriteHeader(http.StatusProxyAuthRequired)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprintf(w, `<html><body><p>via proxy %s</p></body></html>`, r.URL.Host)
+	}))
+	defer forward.Close()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	dead := ln.Addr().String()
+	ln.Close()
+
+	pool, err := NewPool(&BrowserOptions{
+		PoolSize: 1,
+		Timeout:  int(testTimeout.Seconds()),
+		Proxy: config.ProxyConfig{
+			URLs: []string{"http://" + dead, "http://user:pass@" + forward.Listener.Addr().String()},
+		},
+	}, metrics.New())
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	const page = "http://reader.test/"
+	var text string
+	err = pool.ExecuteURL(ctx, page, func(ctx context.Context) error {
+		return chromedp.Run(ctx,
+			pool.Setup(page, nil),
+			chromedp.Navigate(page),
+			chromedp.Text("body", &text, chromedp.ByQuery),
+		)
+	})
+	require.NoError(t, err)
+	assert.Contains(t, text, "via proxy reader.test")
+}
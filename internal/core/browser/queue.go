@@ -0,0 +1,123 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	commonmetrics "github.com/ncecere/reader-go/internal/common/metrics"
+)
+
+// defaultMaxQueue is used when BrowserOptions.MaxQueue is 0
+const defaultMaxQueue = 100
+
+// ErrPoolBusy is returned when no tab can be acquired, because the wait queue is full
+// or the wait timed out. Callers should retry later.
+var ErrPoolBusy = errors.New("browser pool busy")
+
+// acquireSlot reserves one of the PoolSize tab slots. When all are taken, callers wait
+// in FIFO order until a slot is released, ctx is done or the queue timeout passes.
+func (p *Pool) acquireSlot(ctx context.Context) error {
+	p.mu.Lock()
+
+	if p.active < p.opts.PoolSize {
+		p.active++
+		p.updateMetrics()
+		p.mu.Unlock()
+		return nil
+	}
+
+	if max := p.maxQueue(); max > 0 && len(p.queue) >= max {
+		waiting := len(p.queue)
+		p.mu.Unlock()
+		commonmetrics.BrowserQueueRejections.Inc()
+		return fmt.Errorf("%w: %d requests already waiting", ErrPoolBusy, waiting)
+	}
+
+	wait := make(chan struct{}, 1)
+	p.queue = append(p.queue, wait)
+	commonmetrics.BrowserQueueDepth.Set(float64(len(p.queue)))
+	p.mu.Unlock()
+
+	start := time.Now()
+	defer func() {
+		commonmetrics.BrowserQueueWait.Observe(time.Since(start).Seconds())
+	}()
+
+	timeout := p.queueTimeout()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	var err error
+	select {
+	case <-wait:
+		return nil
+	case <-ctx.Done():
+		err = ctx.Err()
+	case <-timer.C:
+		commonmetrics.BrowserQueueRejections.Inc()
+		err = fmt.Errorf("%w: no browser available after %s", ErrPoolBusy, timeout)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.dequeue(wait) {
+		// A slot was handed over as we gave up; pass it on
+		p.handOff()
+	}
+	return err
+}
+
+// releaseSlot frees a tab slot
+func (p *Pool) releaseSlot() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.handOff()
+}
+
+// handOff gives a slot to the longest waiting request, or frees it. The caller must hold p.mu.
+func (p *Pool) handOff() {
+	if len(p.queue) == 0 {
+		p.active--
+		p.updateMetrics()
+		return
+	}
+
+	wait := p.queue[0]
+	p.queue = p.queue[1:]
+	commonmetrics.BrowserQueueDepth.Set(float64(len(p.queue)))
+	wait <- struct{}{}
+}
+
+// dequeue removes a waiting request from the queue, reporting whether it was still there.
+// The caller must hold p.mu.
+func (p *Pool) dequeue(wait chan struct{}) bool {
+	for i, w := range p.queue {
+		if w == wait {
+			p.queue = append(p.queue[:i], p.queue[i+1:]...)
+			commonmetrics.BrowserQueueDepth.Set(float64(len(p.queue)))
+			return true
+		}
+	}
+	return false
+}
+
+// maxQueue returns how many requests may wait for a tab; 0 is unlimited
+func (p *Pool) maxQueue() int {
+	switch {
+	case p.opts.MaxQueue < 0:
+		return 0
+	case p.opts.MaxQueue == 0:
+		return defaultMaxQueue
+	}
+	return p.opts.MaxQueue
+}
+
+// queueTimeout returns how long a request waits for a tab, the request timeout by default
+func (p *Pool) queueTimeout() time.Duration {
+	if p.opts.QueueTimeout > 0 {
+		return time.Duration(p.opts.QueueTimeout) * time.Second
+	}
+	return time.Duration(p.opts.Timeout) * time.Second
+}
@@ -0,0 +1,101 @@
+package browser
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	commonmetrics "github.com/ncecere/reader-go/internal/common/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// occupy opens every tab the pool allows and returns them
+func occupy(t *testing.T, p *Pool) []*Tab {
+	var held []*Tab
+	for i := 0; i < p.opts.PoolSize; i++ {
+		tab, err := p.getTab(context.Background(), nil)
+		require.NoError(t, err)
+		held = append(held, tab)
+	}
+	return held
+}
+
+// waitForQueue blocks until n requests are waiting
+func waitForQueue(t *testing.T, p *Pool, n int) {
+	require.Eventually(t, func() bool {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		return len(p.queue) == n
+	}, time.Second, time.Millisecond)
+}
+
+func TestQueueServesWaitersInOrder(t *testing.T) {
+	p, _ := newFakePool(t, &BrowserOptions{MaxUses: -1})
+	held := occupy(t, p)
+
+	order := make(chan int, 3)
+	for i := 0; i < 3; i++ {
+		go func(i int) {
+			tab, err := p.getTab(context.Background(), nil)
+			if err == nil {
+				order <- i
+				p.closeTab(tab)
+			}
+		}(i)
+		waitForQueue(t, p, i+1)
+	}
+
+	p.closeTab(held[0])
+	for i := 0; i < 3; i++ {
+		select {
+		case got := <-order:
+			assert.Equal(t, i, got)
+		case <-time.After(time.Second):
+			t.Fatal("waiter was not served")
+		}
+	}
+}
+
+func TestQueueRejectsWhenFull(t *testing.T) {
+	p, _ := newFakePool(t, &BrowserOptions{MaxQueue: 1})
+	occupy(t, p)
+	before := testutil.ToFloat64(commonmetrics.BrowserQueueRejections)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _, _ = p.getTab(ctx, nil) }()
+	waitForQueue(t, p, 1)
+
+	_, err := p.getTab(context.Background(), nil)
+	assert.ErrorIs(t, err, ErrPoolBusy)
+	assert.Equal(t, before+1, testutil.ToFloat64(commonmetrics.BrowserQueueRejections))
+
+	err = p.Execute(context.Background(), func(context.Context) error { return nil })
+	assert.ErrorIs(t, err, ErrPoolBusy)
+}
+
+func TestQueueWaitEndsWithContextOrTimeout(t *testing.T) {
+	p, _ := newFakePool(t, &BrowserOptions{QueueTimeout: 1})
+	held := occupy(t, p)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := p.getTab(ctx, nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	start := time.Now()
+	_, err = p.getTab(context.Background(), nil)
+	assert.ErrorIs(t, err, ErrPoolBusy)
+	assert.GreaterOrEqual(t, time.Since(start), time.Second)
+
+	p.mu.Lock()
+	assert.Empty(t, p.queue, "abandoned waiters leave the queue")
+	p.mu.Unlock()
+
+	for _, tab := range held {
+		p.closeTab(tab)
+	}
+	assert.Zero(t, p.active)
+}
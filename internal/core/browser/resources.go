@@ -0,0 +1,110 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/chromedp/cdproto/performance"
+	"github.com/chromedp/chromedp"
+	"github.com/ncecere/reader-go/internal/common/logger"
+	commonmetrics "github.com/ncecere/reader-go/internal/common/metrics"
+	"go.uber.org/zap"
+)
+
+// heapTimeout bounds reading a tab's JS heap size
+const heapTimeout = time.Second
+
+// sampleResources reads the RSS and CPU usage of every Chrome process tree, exports
+// them and recycles processes above the memory limit
+func (p *Pool) sampleResources() {
+	p.mu.Lock()
+	live := append([]*Instance(nil), p.instances...)
+	p.mu.Unlock()
+
+	commonmetrics.BrowserMemory.Reset()
+	commonmetrics.BrowserCPU.Reset()
+
+	var total uint64
+	var over []*Instance
+	for i, instance := range live {
+		if instance.pid == 0 {
+			continue
+		}
+		usage, err := p.usage(instance.pid)
+		if err != nil {
+			logger.Log.Debug("Failed to read browser process usage",
+				zap.Int("pid", instance.pid), zap.Error(err))
+			continue
+		}
+
+		label := strconv.Itoa(i)
+		total += usage.RSSBytes
+		commonmetrics.BrowserMemory.WithLabelValues(label).Set(float64(usage.RSSBytes))
+
+		now := time.Now()
+		p.mu.Lock()
+		if !instance.sampledAt.IsZero() {
+			elapsed := now.Sub(instance.sampledAt).Seconds()
+			if elapsed > 0 && usage.CPUSeconds >= instance.cpuSeconds {
+				commonmetrics.BrowserCPU.WithLabelValues(label).Set((usage.CPUSeconds - instance.cpuSeconds) / elapsed)
+			}
+		}
+		instance.cpuSeconds = usage.CPUSeconds
+		instance.sampledAt = now
+		p.mu.Unlock()
+
+		if limit := p.memoryLimit(); limit > 0 && usage.RSSBytes > limit {
+			logger.Log.Warn("Browser process exceeded memory limit",
+				zap.Uint64("rss_mb", usage.RSSBytes>>20),
+				zap.Uint64("limit_mb", limit>>20),
+				zap.Int("processes", usage.Processes))
+			over = append(over, instance)
+		}
+	}
+
+	p.metrics.UpdateMemoryUsage(total >> 20)
+
+	for _, instance := range over {
+		p.recycle(instance, "memory")
+	}
+}
+
+// observeHeap records the JS heap a request's page used before its tab is closed
+func (p *Pool) observeHeap(tab *Tab) {
+	if tab.ctx.Err() != nil {
+		return
+	}
+	bytes, err := p.heap(tab)
+	if err != nil {
+		logger.Log.Debug("Failed to read JS heap size", zap.Error(err))
+		return
+	}
+	commonmetrics.BrowserJSHeap.Observe(bytes)
+}
+
+// jsHeapUsed returns the tab's used JS heap from Performance.getMetrics
+func jsHeapUsed(tab *Tab) (float64, error) {
+	ctx, cancel := context.WithTimeout(tab.ctx, heapTimeout)
+	defer cancel()
+
+	var used float64
+	err := chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		if err := performance.Enable().Do(ctx); err != nil {
+			return err
+		}
+		metrics, err := performance.GetMetrics().Do(ctx)
+		if err != nil {
+			return err
+		}
+		for _, metric := range metrics {
+			if metric.Name == "JSHeapUsedSize" {
+				used = metric.Value
+				return nil
+			}
+		}
+		return fmt.Errorf("JSHeapUsedSize metric missing")
+	}))
+	return used, err
+}
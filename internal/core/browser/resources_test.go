@@ -0,0 +1,109 @@
+package browser
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"runtime"
+	"testing"
+	"time"
+
+	commonmetrics "github.com/ncecere/reader-go/internal/common/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseProcStat(t *testing.T) {
+	line := "4242 (chrome (renderer) x) S 4200 4200 4200 0 -1 4194560 1 0 0 0 150 50 0 0 20 0 12 0 100 123456 2560 18446744073709551615"
+
+	stat, err := parseProcStat(line)
+	require.NoError(t, err)
+	assert.Equal(t, 4242, stat.pid)
+	assert.Equal(t, 4200, stat.ppid)
+	assert.Equal(t, uint64(200), stat.cpuTicks)
+	assert.Equal(t, uint64(2560), stat.rssPages)
+
+	_, err = parseProcStat("4242 (chrome) S 1 2")
+	assert.Error(t, err)
+}
+
+func TestProcessTreeUsageIncludesChildren(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("process statistics are read from /proc")
+	}
+
+	self, err := processTreeUsage(os.Getpid())
+	require.NoError(t, err)
+	assert.Positive(t, self.RSSBytes)
+
+	child := exec.Command("sleep", "5")
+	require.NoError(t, child.Start())
+	t.Cleanup(func() {
+		_ = child.Process.Kill()
+		_ = child.Wait()
+	})
+
+	tree, err := processTreeUsage(os.Getpid())
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, tree.Processes, self.Processes+1)
+
+	_, err = processTreeUsage(-1)
+	assert.Error(t, err)
+}
+
+func TestPoolSamplesResources(t *testing.T) {
+	p, _ := newFakePool(t, &BrowserOptions{Processes: 2, MemoryLimitMB: -1})
+	for i, instance := range instances(p) {
+		instance.pid = 100 + i
+	}
+	cpu := 1.0
+	p.usage = func(pid int) (processUsage, error) {
+		return processUsage{RSSBytes: uint64(pid-99) * 100 << 20, CPUSeconds: cpu, Processes: 4}, nil
+	}
+
+	p.sampleResources()
+	assert.Equal(t, float64(100<<20), testutil.ToFloat64(commonmetrics.BrowserMemory.WithLabelValues("0")))
+	assert.Equal(t, float64(200<<20), testutil.ToFloat64(commonmetrics.BrowserMemory.WithLabelValues("1")))
+	assert.Equal(t, uint64(300), p.metrics.GetStats().CurrentMemoryMB)
+
+	// CPU usage is the CPU time used between samples over the time between them
+	for _, instance := range instances(p) {
+		instance.sampledAt = time.Now().Add(-2 * time.Second)
+	}
+	cpu = 2.0
+	p.sampleResources()
+	assert.InDelta(t, 0.5, testutil.ToFloat64(commonmetrics.BrowserCPU.WithLabelValues("0")), 0.05)
+}
+
+func TestPoolRecyclesOverMemoryLimit(t *testing.T) {
+	p, launcher := newFakePool(t, &BrowserOptions{Processes: 2, MemoryLimitMB: 150})
+	all := instances(p)
+	for i, instance := range all {
+		instance.pid = 100 + i
+	}
+	p.usage = func(pid int) (processUsage, error) {
+		return processUsage{RSSBytes: uint64(pid-99) * 100 << 20}, nil
+	}
+	before := recycles("memory")
+
+	p.sampleResources()
+
+	assert.Equal(t, before+1, recycles("memory"))
+	assert.Equal(t, 3, launcher.count())
+	assert.Contains(t, instances(p), all[0])
+	assert.NotContains(t, instances(p), all[1])
+}
+
+func TestPoolObservesJSHeap(t *testing.T) {
+	p, _ := newFakePool(t, &BrowserOptions{})
+	measured := 0
+	p.heap = func(tab *Tab) (float64, error) {
+		assert.NoError(t, tab.ctx.Err(), "the heap is read before the tab closes")
+		measured++
+		return 8 << 20, nil
+	}
+
+	require.NoError(t, p.Execute(context.Background(), func(context.Context) error { return nil }))
+	assert.Equal(t, 1, measured)
+}
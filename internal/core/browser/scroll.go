@@ -0,0 +1,146 @@
+package browser
+
+import (
+	"context"
+	"time"
+
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+	"github.com/ncecere/reader-go/internal/common/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultMaxScrolls is used when BrowserOptions.MaxScrolls is 0
+	defaultMaxScrolls = 20
+	// defaultScrollTimeout is used when BrowserOptions.ScrollTimeout is 0
+	defaultScrollTimeout = 10 * time.Second
+	// scrollSettle is how long new content gets to load after each scroll
+	scrollSettle = 500 * time.Millisecond
+)
+
+// scrollScript scrolls to the bottom one viewport at a time, so content that loads
+// when it becomes visible is triggered on the way, and returns the page height
+const scrollScript = `(async () => {
+	const height = () => Math.max(document.body.scrollHeight, document.documentElement.scrollHeight);
+	for (let i = 0, y = window.scrollY; i < 200 && y < height(); i++, y += window.innerHeight) {
+		window.scrollTo(0, y);
+		await new Promise((resolve) => setTimeout(resolve, 50));
+	}
+	window.scrollTo(0, height());
+	return height();
+})()`
+
+// lazyImagesScript copies lazy-loading attributes such as data-src into src and
+// srcset and turns off native lazy loading, so images resolve without being scrolled
+// into view. It returns the number of images changed.
+const lazyImagesScript = `(() => {
+	const srcAttrs = ['data-src', 'data-lazy-src', 'data-original', 'data-lazy', 'data-url'];
+	const srcsetAttrs = ['data-srcset', 'data-lazy-srcset'];
+	const usable = (value) => value && !value.startsWith('data:');
+	let changed = 0;
+	for (const el of document.querySelectorAll('img, picture source')) {
+		let swapped = false;
+		if (el.tagName === 'IMG') {
+			const src = srcAttrs.map((a) => el.getAttribute(a)).find(usable);
+			if (src && src !== el.getAttribute('src')) {
+				el.setAttribute('src', src);
+				swapped = true;
+			}
+			if (el.getAttribute('loading') === 'lazy') {
+				el.removeAttribute('loading');
+				swapped = true;
+			}
+		}
+		const srcset = srcsetAttrs.map((a) => el.getAttribute(a)).find(usable);
+		if (srcset && srcset !== el.getAttribute('srcset')) {
+			el.setAttribute('srcset', srcset);
+			swapped = true;
+		}
+		if (swapped) changed++;
+	}
+	return changed;
+})()`
+
+type autoScrollKey struct{}
+
+// WithAutoScroll returns a context whose page fetches scroll through the page before
+// reading it
+func WithAutoScroll(ctx context.Context) context.Context {
+	return context.WithValue(ctx, autoScrollKey{}, true)
+}
+
+// AutoScrollRequested reports whether ctx asks for auto-scrolling
+func AutoScrollRequested(ctx context.Context) bool {
+	requested, _ := ctx.Value(autoScrollKey{}).(bool)
+	return requested
+}
+
+// AutoScroll returns an action that scrolls to the bottom of the page until its height
+// stops growing, the pool's scroll limit is reached or its scroll timeout passes
+func (p *Pool) AutoScroll() chromedp.Action {
+	maxScrolls := p.opts.MaxScrolls
+	if maxScrolls <= 0 {
+		maxScrolls = defaultMaxScrolls
+	}
+	timeout := defaultScrollTimeout
+	if p.opts.ScrollTimeout > 0 {
+		timeout = time.Duration(p.opts.ScrollTimeout) * time.Second
+	}
+	return autoScroll(maxScrolls, timeout, scrollSettle)
+}
+
+// autoScroll scrolls at most maxScrolls times within timeout, waiting settle after
+// each scroll for more content. Hitting a limit is not an error.
+func autoScroll(maxScrolls int, timeout, settle time.Duration) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		scrollCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		scrolls, last := 0, int64(-1)
+		for ; scrolls < maxScrolls; scrolls++ {
+			var height int64
+			err := chromedp.Evaluate(scrollScript, &height, func(p *runtime.EvaluateParams) *runtime.EvaluateParams {
+				return p.WithAwaitPromise(true)
+			}).Do(scrollCtx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				// Out of scroll time; read what has loaded so far
+				break
+			}
+			if height == last {
+				break
+			}
+			last = height
+
+			if err := sleep(scrollCtx, settle); err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				break
+			}
+		}
+
+		logger.Log.Debug("Scrolled page",
+			zap.Int("scrolls", scrolls),
+			zap.Int64("height", last))
+		return chromedp.Evaluate(`window.scrollTo(0, 0)`, nil).Do(ctx)
+	})
+}
+
+// ResolveLazyImages returns an action that makes lazy-loaded images point at their
+// real source, so the page HTML contains usable image URLs
+func ResolveLazyImages() chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		var changed int
+		if err := chromedp.Evaluate(lazyImagesScript, &changed).Do(ctx); err != nil {
+			return err
+		}
+		if changed > 0 {
+			logger.Log.Debug("Resolved lazy-loaded images", zap.Int("images", changed))
+		}
+		return nil
+	})
+}
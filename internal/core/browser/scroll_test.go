@@ -0,0 +1,91 @@
+package browser_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chromedp/chromedp"
+	"github.com/ncecere/reader-go/internal/core/browser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutoScrollRequested(t *testing.T) {
+	ctx := context.Background()
+	assert.False(t, browser.AutoScrollRequested(ctx))
+	assert.True(t, browser.AutoScrollRequested(browser.WithAutoScroll(ctx)))
+}
+
+func TestPool_AutoScroll(t *testing.T) {
+	// Every time the reader nears the bottom another page of the feed is appended, up to 5
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`<!DOCTYPE html><html><body>
+			<div id="feed"><p style="height:2000px">Item 1</p></div>
+			<script>
+				let items = 1;
+				window.addEventListener('scroll', () => {
+					if (items < 5 && window.innerHeight + window.scrollY >= document.body.scrollHeight - 10) {
+						items++;
+						const p = document.createElement('p');
+						p.style.height = '2000px';
+						p.textContent = 'Item ' + items;
+						document.getElementById('feed').appendChild(p);
+					}
+				});
+			</script>
+		</body></html>`))
+	}))
+	defer ts.Close()
+
+	pool := browser.SetupTestPool(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var text string
+	err := pool.Execute(ctx, func(ctx context.Context) error {
+		return chromedp.Run(ctx,
+			chromedp.Navigate(ts.URL),
+			chromedp.WaitReady("body", chromedp.ByQuery),
+			pool.AutoScroll(),
+			chromedp.Text("#feed", &text, chromedp.ByQuery),
+		)
+	})
+	require.NoError(t, err)
+	assert.Contains(t, text, "Item 5")
+}
+
+func TestResolveLazyImages(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`<!DOCTYPE html><html><body>
+			<img id="a" src="data:image/gif;base64,R0lGODlhAQABAAAAACw=" data-src="/photo.jpg">
+			<img id="b" data-lazy-src="/lazy.jpg" loading="lazy">
+			<picture><source data-srcset="/wide.webp 2x"><img id="c" src="/fallback.jpg"></picture>
+		</body></html>`))
+	}))
+	defer ts.Close()
+
+	pool := browser.SetupTestPool(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	var html string
+	err := pool.Execute(ctx, func(ctx context.Context) error {
+		return chromedp.Run(ctx,
+			chromedp.Navigate(ts.URL),
+			chromedp.WaitReady("body", chromedp.ByQuery),
+			browser.ResolveLazyImages(),
+			chromedp.OuterHTML("body", &html, chromedp.ByQuery),
+		)
+	})
+	require.NoError(t, err)
+	assert.Contains(t, html, `id="a" src="/photo.jpg"`)
+	assert.Contains(t, html, `src="/lazy.jpg"`)
+	assert.NotContains(t, strings.ToLower(html), `loading="lazy"`)
+	assert.Contains(t, html, `srcset="/wide.webp 2x"`)
+}
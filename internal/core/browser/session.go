@@ -0,0 +1,261 @@
+package browser
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+	"github.com/ncecere/reader-go/internal/common/config"
+	"github.com/ncecere/reader-go/internal/core/domain"
+)
+
+// Cookie is set in a tab before its page loads
+type Cookie struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	// Domain defaults to the page host; a leading dot includes subdomains
+	Domain   string `json:"domain,omitempty"`
+	Path     string `json:"path,omitempty"`
+	Expires  int64  `json:"expires,omitempty"` // Unix seconds; 0 is a session cookie
+	Secure   bool   `json:"secure,omitempty"`
+	HTTPOnly bool   `json:"http_only,omitempty"`
+}
+
+// BasicAuth holds credentials for HTTP basic authentication challenges
+type BasicAuth struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// Session is the state a page is fetched with: cookies, extra request headers and
+// basic auth credentials. It lives in the tab's own browser context, so it is gone
+// when the tab closes and never reaches other requests.
+type Session struct {
+	Cookies   []Cookie          `json:"cookies,omitempty"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	BasicAuth *BasicAuth        `json:"basic_auth,omitempty"`
+}
+
+// empty reports whether the session sets nothing
+func (s *Session) empty() bool {
+	return s == nil || (len(s.Cookies) == 0 && len(s.Headers) == 0 && s.BasicAuth == nil)
+}
+
+// merge returns s with other applied on top: other's cookies are set after s's and
+// its headers and credentials take precedence
+func (s *Session) merge(other *Session) *Session {
+	if s.empty() {
+		return other
+	}
+	if other.empty() {
+		return s
+	}
+
+	merged := &Session{
+		Cookies:   append(append([]Cookie(nil), s.Cookies...), other.Cookies...),
+		Headers:   make(map[string]string, len(s.Headers)+len(other.Headers)),
+		BasicAuth: s.BasicAuth,
+	}
+	for name, value := range s.Headers {
+		merged.Headers[name] = value
+	}
+	for name, value := range other.Headers {
+		merged.Headers[name] = value
+	}
+	if other.BasicAuth != nil {
+		merged.BasicAuth = other.BasicAuth
+	}
+	return merged
+}
+
+type sessionKey struct{}
+
+// WithSession returns a context whose page fetches use session in addition to the
+// sessions configured for the page's domain
+func WithSession(ctx context.Context, session *Session) context.Context {
+	return context.WithValue(ctx, sessionKey{}, session)
+}
+
+// SessionFrom returns the session requested for ctx, or nil
+func SessionFrom(ctx context.Context) *Session {
+	session, _ := ctx.Value(sessionKey{}).(*Session)
+	if session.empty() {
+		return nil
+	}
+	return session
+}
+
+// ParseCookieHeader parses "name=value; name2=value2" as sent in a Cookie header
+func ParseCookieHeader(header string) []Cookie {
+	req := http.Request{Header: http.Header{"Cookie": {header}}}
+	var cookies []Cookie
+	for _, c := range req.Cookies() {
+		cookies = append(cookies, Cookie{Name: c.Name, Value: c.Value})
+	}
+	return cookies
+}
+
+// LoadCookieFile reads cookies from a Netscape cookie jar, as written by curl, wget
+// and browser export extensions
+func LoadCookieFile(path string) ([]Cookie, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cookie file: %w", err)
+	}
+	defer file.Close()
+
+	var cookies []Cookie
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		httpOnly := strings.HasPrefix(text, "#HttpOnly_")
+		text = strings.TrimPrefix(text, "#HttpOnly_")
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		// domain, include subdomains, path, secure, expires, name, value
+		fields := strings.Split(text, "\t")
+		if len(fields) < 7 {
+			return nil, fmt.Errorf("cookie file %s line %d: want 7 tab-separated fields, got %d", path, line, len(fields))
+		}
+		expires, err := strconv.ParseInt(fields[4], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("cookie file %s line %d: invalid expiry %q", path, line, fields[4])
+		}
+
+		domain := fields[0]
+		if strings.EqualFold(fields[1], "TRUE") && !strings.HasPrefix(domain, ".") {
+			domain = "." + domain
+		}
+		cookies = append(cookies, Cookie{
+			Name:     fields[5],
+			Value:    strings.Join(fields[6:], "\t"),
+			Domain:   domain,
+			Path:     fields[2],
+			Expires:  expires,
+			Secure:   strings.EqualFold(fields[3], "TRUE"),
+			HTTPOnly: httpOnly,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read cookie file: %w", err)
+	}
+	return cookies, nil
+}
+
+// Sessions holds the sessions configured per domain
+type Sessions struct {
+	sites map[string]*Session
+}
+
+// NewSessions builds per-domain sessions from the configuration, reading cookie files.
+// Cookies without a domain apply to the configured domain and its subdomains.
+func NewSessions(cfg map[string]config.SessionConfig) (*Sessions, error) {
+	s := &Sessions{sites: make(map[string]*Session, len(cfg))}
+	for site, sc := range cfg {
+		site = domain.Normalize(site)
+		session := &Session{Headers: sc.Headers}
+
+		for _, c := range sc.Cookies {
+			if c.Name == "" {
+				return nil, fmt.Errorf("session for %s has a cookie without a name", site)
+			}
+			domain := c.Domain
+			if domain == "" {
+				domain = "." + site
+			}
+			session.Cookies = append(session.Cookies, Cookie{
+				Name:     c.Name,
+				Value:    c.Value,
+				Domain:   domain,
+				Path:     c.Path,
+				Secure:   c.Secure,
+				HTTPOnly: c.HTTPOnly,
+			})
+		}
+		if sc.CookieFile != "" {
+			cookies, err := LoadCookieFile(sc.CookieFile)
+			if err != nil {
+				return nil, fmt.Errorf("session for %s: %w", site, err)
+			}
+			session.Cookies = append(session.Cookies, cookies...)
+		}
+		if sc.BasicAuth != nil {
+			session.BasicAuth = &BasicAuth{Username: sc.BasicAuth.Username, Password: sc.BasicAuth.Password}
+		}
+
+		s.sites[site] = session
+	}
+	return s, nil
+}
+
+// For returns the session of the most specific domain matching the page, or nil
+func (s *Sessions) For(pageURL string) *Session {
+	if s == nil {
+		return nil
+	}
+	for _, parent := range domain.Parents(domain.Hostname(pageURL)) {
+		if session, ok := s.sites[parent]; ok {
+			return session
+		}
+	}
+	return nil
+}
+
+// applySession sets the session's cookies in the tab's browser context and its
+// headers on every request the tab makes
+func applySession(session *Session, pageURL string) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		if session.empty() {
+			return nil
+		}
+
+		if len(session.Cookies) > 0 {
+			params := make([]*network.CookieParam, 0, len(session.Cookies))
+			for _, c := range session.Cookies {
+				param := &network.CookieParam{
+					Name:     c.Name,
+					Value:    c.Value,
+					Domain:   c.Domain,
+					Path:     c.Path,
+					Secure:   c.Secure,
+					HTTPOnly: c.HTTPOnly,
+				}
+				if c.Domain == "" {
+					param.URL = pageURL
+				}
+				if c.Expires > 0 {
+					expires := cdp.TimeSinceEpoch(time.Unix(c.Expires, 0))
+					param.Expires = &expires
+				}
+				params = append(params, param)
+			}
+			if err := network.SetCookies(params).Do(ctx); err != nil {
+				return fmt.Errorf("failed to set cookies: %w", err)
+			}
+		}
+
+		if len(session.Headers) > 0 {
+			headers := make(network.Headers, len(session.Headers))
+			for name, value := range session.Headers {
+				headers[name] = value
+			}
+			if err := network.Enable().Do(ctx); err != nil {
+				return err
+			}
+			if err := network.SetExtraHTTPHeaders(headers).Do(ctx); err != nil {
+				return fmt.Errorf("failed to set headers: %w", err)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,153 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chromedp/chromedp"
+	"github.com/ncecere/reader-go/internal/common/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadCookieFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cookies.txt")
+	require.NoError(t, os.WriteFile(path, []byte(
+		"# Netscape HTTP Cookie File\n"+
+			"\n"+
+			".example.com\tTRUE\t/\tTRUE\t1893456000\tsid\tabc123\n"+
+			"#HttpOnly_app.example.com\tFALSE\t/account\tFALSE\t0\ttoken\tx\ty\n"+
+			"wiki.test\tTRUE\t/\tFALSE\t0\tlang\ten\n",
+	), 0o644))
+
+	cookies, err := LoadCookieFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, []Cookie{
+		{Name: "sid", Value: "abc123", Domain: ".example.com", Path: "/", Expires: 1893456000, Secure: true},
+		{Name: "token", Value: "x\ty", Domain: "app.example.com", Path: "/account", HTTPOnly: true},
+		{Name: "lang", Value: "en", Domain: ".wiki.test", Path: "/"},
+	}, cookies)
+
+	require.NoError(t, os.WriteFile(path, []byte("example.com\tTRUE\t/\n"), 0o644))
+	_, err = LoadCookieFile(path)
+	assert.ErrorContains(t, err, "line 1")
+
+	_, err = LoadCookieFile(filepath.Join(t.TempDir(), "missing.txt"))
+	assert.Error(t, err)
+}
+
+func TestParseCookieHeader(t *testing.T) {
+	assert.Equal(t, []Cookie{{Name: "a", Value: "1"}, {Name: "b", Value: "two"}},
+		ParseCookieHeader("a=1; b=two"))
+	assert.Empty(t, ParseCookieHeader(";"))
+}
+
+func TestSessionsFor(t *testing.T) {
+	s, err := NewSessions(map[string]config.SessionConfig{
+		"example.com": {
+			Cookies: []config.CookieConfig{{Name: "sid", Value: "1"}},
+			Headers: map[string]string{"X-Team": "docs"},
+		},
+		"admin.example.com": {
+			BasicAuth: &config.BasicAuthConfig{Username: "admin", Password: "secret"},
+		},
+	})
+	require.NoError(t, err)
+
+	site := s.For("https://www.example.com/page")
+	require.NotNil(t, site)
+	assert.Equal(t, []Cookie{{Name: "sid", Value: "1", Domain: ".example.com"}}, site.Cookies)
+
+	admin := s.For("https://admin.example.com/")
+	require.NotNil(t, admin)
+	assert.Equal(t, &BasicAuth{Username: "admin", Password: "secret"}, admin.BasicAuth)
+	assert.Empty(t, admin.Cookies, "the most specific domain wins")
+
+	assert.Nil(t, s.For("https://other.test/"))
+	assert.Nil(t, (*Sessions)(nil).For("https://example.com/"))
+
+	_, err = NewSessions(map[string]config.SessionConfig{
+		"example.com": {Cookies: []config.CookieConfig{{Value: "1"}}},
+	})
+	assert.ErrorContains(t, err, "without a name")
+}
+
+func TestSessionMerge(t *testing.T) {
+	configured := &Session{
+		Cookies:   []Cookie{{Name: "sid", Value: "configured"}},
+		Headers:   map[string]string{"X-Team": "docs", "X-Env": "prod"},
+		BasicAuth: &BasicAuth{Username: "bot"},
+	}
+	request := &Session{
+		Cookies: []Cookie{{Name: "sid", Value: "request"}},
+		Headers: map[string]string{"X-Env": "staging"},
+	}
+
+	merged := configured.merge(request)
+	assert.Equal(t, []Cookie{{Name: "sid", Value: "configured"}, {Name: "sid", Value: "request"}}, merged.Cookies,
+		"request cookies are set last and replace configured ones")
+	assert.Equal(t, map[string]string{"X-Team": "docs", "X-Env": "staging"}, merged.Headers)
+	assert.Equal(t, "bot", merged.BasicAuth.Username)
+	assert.Equal(t, map[string]string{"X-Team": "docs", "X-Env": "prod"}, configured.Headers, "configured session unchanged")
+
+	assert.Same(t, request, (*Session)(nil).merge(request))
+	assert.Same(t, configured, configured.merge(nil))
+}
+
+func TestSessionFrom(t *testing.T) {
+	ctx := context.Background()
+	assert.Nil(t, SessionFrom(ctx))
+	assert.Nil(t, SessionFrom(WithSession(ctx, &Session{})), "empty sessions are ignored")
+
+	session := &Session{Cookies: []Cookie{{Name: "a", Value: "1"}}}
+	assert.Same(t, session, SessionFrom(WithSession(ctx, session)))
+}
+
+func TestPool_Setup(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "alice" || pass != "secret" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="test"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		sid, _ := r.Cookie("sid")
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprintf(w, `<html><body><p id="out">%s %s</p></body></html>`, sid.Value, r.Header.Get("X-Team"))
+	}))
+	defer ts.Close()
+
+	pool := SetupTestPool(t)
+	read := func(session *Session) (string, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+		defer cancel()
+
+		var text string
+		err := pool.Execute(ctx, func(ctx context.Context) error {
+			return chromedp.Run(ctx,
+				pool.Setup(ts.URL, session),
+				chromedp.Navigate(ts.URL),
+				chromedp.Text("body", &text, chromedp.ByQuery),
+			)
+		})
+		return text, err
+	}
+
+	text, err := read(&Session{
+		Cookies:   []Cookie{{Name: "sid", Value: "abc"}},
+		Headers:   map[string]string{"X-Team": "docs"},
+		BasicAuth: &BasicAuth{Username: "alice", Password: "secret"},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, text, "abc docs")
+
+	// The next tab starts from a clean browser context
+	text, _ = read(nil)
+	assert.NotContains(t, text, "abc")
+}
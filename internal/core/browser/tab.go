@@ -0,0 +1,190 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/chromedp/cdproto/target"
+	"github.com/chromedp/chromedp"
+	"github.com/ncecere/reader-go/internal/common/logger"
+	"github.com/ncecere/reader-go/internal/core/proxy"
+	"go.uber.org/zap"
+)
+
+// Tab is an incognito tab with its own browser context, so cookies, storage and cache
+// are never shared between requests
+type Tab struct {
+	ctx      context.Context
+	cancel   context.CancelFunc
+	instance *Instance
+}
+
+// getTab waits for a tab slot and opens a tab connecting through px on the least busy instance
+func (p *Pool) getTab(ctx context.Context, px *proxy.Proxy) (*Tab, error) {
+	if err := p.acquireSlot(ctx); err != nil {
+		return nil, err
+	}
+
+	tab, err := p.newTab(px)
+	if err != nil {
+		p.releaseSlot()
+		return nil, err
+	}
+	return tab, nil
+}
+
+// newTab opens a tab on the instance with the fewest open tabs
+func (p *Pool) newTab(px *proxy.Proxy) (*Tab, error) {
+	p.mu.Lock()
+	instance := p.pickInstance()
+	if instance == nil {
+		// Every instance was lost; launch one rather than fail the request
+		var err error
+		if instance, err = p.relaunch(); err != nil {
+			p.mu.Unlock()
+			return nil, err
+		}
+	}
+	instance.tabs++
+	instance.uses++
+	p.updateMetrics()
+	p.mu.Unlock()
+
+	tab, err := p.openTab(instance, px)
+	if err != nil {
+		p.mu.Lock()
+		instance.tabs--
+		p.mu.Unlock()
+
+		// A browser that cannot open tabs is of no further use
+		logger.Log.Warn("Failed to open browser tab", zap.Error(err))
+		p.recycleAsync(instance, "unhealthy")
+		return nil, err
+	}
+	return tab, nil
+}
+
+// pendingLaunch is a launch that requests finding no live instance wait for
+type pendingLaunch struct {
+	done     chan struct{}
+	instance *Instance
+	err      error
+}
+
+// relaunch launches an instance in place of a lost one. Concurrent callers share a
+// single launch, so a burst of requests after every process died starts one process
+// and the pool keeps its size; the health check replaces the others. The caller must
+// hold p.mu, which is released while the launch runs.
+func (p *Pool) relaunch() (*Instance, error) {
+	if pending := p.relaunching; pending != nil {
+		p.mu.Unlock()
+		<-pending.done
+		p.mu.Lock()
+		return pending.instance, pending.err
+	}
+
+	pending := &pendingLaunch{done: make(chan struct{})}
+	p.relaunching = pending
+	p.mu.Unlock()
+	instance, err := p.launch()
+	p.mu.Lock()
+	p.relaunching = nil
+	defer close(pending.done)
+
+	if err == nil && p.closed {
+		instance.cancel()
+		err = errors.New("browser pool closed")
+	}
+	if err != nil {
+		pending.err = err
+		return nil, err
+	}
+
+	replaced := false
+	for i, inst := range p.instances {
+		if inst.ctx.Err() != nil && !inst.retiring {
+			inst.cancel()
+			p.instances[i] = instance
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		p.instances = append(p.instances, instance)
+	}
+	pending.instance = instance
+	return instance, nil
+}
+
+// pickInstance returns the live instance with the fewest open tabs, preferring ones
+// that are not being recycled. The caller must hold p.mu.
+func (p *Pool) pickInstance() *Instance {
+	var best *Instance
+	for _, instance := range p.instances {
+		if instance.ctx.Err() != nil {
+			continue
+		}
+		if best == nil ||
+			(best.retiring && !instance.retiring) ||
+			(best.retiring == instance.retiring && instance.tabs < best.tabs) {
+			best = instance
+		}
+	}
+	return best
+}
+
+// closeTab closes a tab, which disposes its browser context, and frees its slot.
+// Its instance is recycled if it crashed or reached its use or age limit.
+func (p *Pool) closeTab(tab *Tab) {
+	tab.cancel()
+
+	p.mu.Lock()
+	instance := tab.instance
+	instance.tabs--
+	reason := p.recycleReason(instance)
+	if instance.retiring && instance.tabs == 0 && !p.contains(instance) {
+		// The last tab of a replaced instance is done
+		instance.cancel()
+	}
+	p.mu.Unlock()
+
+	if reason != "" {
+		p.recycleAsync(instance, reason)
+	}
+	p.releaseSlot()
+}
+
+// contains reports whether instance is still in the pool. The caller must hold p.mu.
+func (p *Pool) contains(instance *Instance) bool {
+	for _, inst := range p.instances {
+		if inst == instance {
+			return true
+		}
+	}
+	return false
+}
+
+// createTab opens a tab in a new incognito browser context of instance. The context
+// connects through px, if it is not nil; bypassed hosts are reached directly.
+func (p *Pool) createTab(instance *Instance, px *proxy.Proxy) (*Tab, error) {
+	var options []chromedp.CreateBrowserContextOption
+	if px != nil {
+		server, err := px.ChromeServer()
+		if err != nil {
+			return nil, err
+		}
+		bypass := strings.Join(p.proxies.Bypass(), ";")
+		options = append(options, func(params *target.CreateBrowserContextParams) *target.CreateBrowserContextParams {
+			return params.WithProxyServer(server).WithProxyBypassList(bypass)
+		})
+	}
+
+	ctx, cancel := chromedp.NewContext(instance.ctx, chromedp.WithNewBrowserContext(options...))
+	if err := chromedp.Run(ctx); err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to open tab: %w", err)
+	}
+	return &Tab{ctx: ctx, cancel: cancel, instance: instance}, nil
+}
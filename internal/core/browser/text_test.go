@@ -5,9 +5,11 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/ncecere/reader-go/internal/core/browser"
 	"github.com/ncecere/reader-go/internal/core/extractors"
 )
 
@@ -29,7 +31,7 @@ func TestTextExtractor_ExtractText(t *testing.T) {
 	defer ts.Close()
 
 	// Create text extractor using test pool
-	pool := setupTestPool(t)
+	pool := browser.SetupTestPool(t)
 	extractor := extractors.NewTextExtractor(pool)
 
 	// Test cases
@@ -78,3 +80,43 @@ func TestTextExtractor_ExtractText(t *testing.T) {
 		})
 	}
 }
+
+func TestTextExtractor_ExtractTextAndHTML(t *testing.T) {
+	var loads int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		atomic.AddInt32(&loads, 1)
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`
+			<!DOCTYPE html>
+			<html>
+			<head><title>Test Page</title><meta property="og:title" content="Shared Title"></head>
+			<body><p>This is a test paragraph.</p></body>
+			</html>
+		`))
+	}))
+	defer ts.Close()
+
+	pool := browser.SetupTestPool(t)
+	extractor := extractors.NewTextExtractor(pool)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	text, html, err := extractor.ExtractTextAndHTML(ctx, ts.URL)
+	if err != nil {
+		t.Fatalf("TextExtractor.ExtractTextAndHTML() error = %v", err)
+	}
+	if !strings.Contains(text, "This is a test paragraph") {
+		t.Errorf("TextExtractor.ExtractTextAndHTML() text = %v, want the paragraph", text)
+	}
+	if !strings.Contains(html, `content="Shared Title"`) {
+		t.Errorf("TextExtractor.ExtractTextAndHTML() html = %v, want the og:title meta tag", html)
+	}
+	if n := atomic.LoadInt32(&loads); n != 1 {
+		t.Errorf("page loaded %d times, want 1", n)
+	}
+}
@@ -0,0 +1,114 @@
+package chunker
+
+import (
+	"fmt"
+)
+
+// Unit selects how chunk sizes are measured
+type Unit string
+
+const (
+	// Tokens measures size in estimated model tokens
+	Tokens Unit = "tokens"
+	// Chars measures size in bytes of markdown
+	Chars Unit = "chars"
+)
+
+// CharsPerToken is the rough ratio used to estimate tokens for English text
+const CharsPerToken = 4
+
+// minChunkBytes is the smallest chunk size accepted; smaller chunks split words apart
+const minChunkBytes = 16
+
+// Options configures how markdown is split into chunks
+type Options struct {
+	Size    int  // Maximum chunk size in Unit
+	Overlap int  // Amount of the previous chunk repeated at the start of the next, in Unit
+	Unit    Unit // Tokens or Chars
+}
+
+// DefaultOptions returns the default chunking configuration
+func DefaultOptions() *Options {
+	return &Options{
+		Size:    512,
+		Overlap: 64,
+		Unit:    Tokens,
+	}
+}
+
+// Chunk is a contiguous slice of the source markdown
+type Chunk struct {
+	Index       int      `json:"index"`
+	Text        string   `json:"text"`
+	HeadingPath []string `json:"heading_path"`
+	SourceURL   string   `json:"source_url,omitempty"`
+	Offset      int      `json:"offset"`
+	Length      int      `json:"length"`
+	Tokens      int      `json:"tokens"`
+}
+
+// EstimateTokens approximates the number of model tokens in text
+func EstimateTokens(text string) int {
+	return (len(text) + CharsPerToken - 1) / CharsPerToken
+}
+
+// Split divides markdown along heading boundaries into chunks that fit the size budget.
+// Sections larger than the budget are split at paragraph, line or word boundaries.
+func Split(markdown, sourceURL string, opts *Options) ([]Chunk, error) {
+	if opts == nil {
+		opts = DefaultOptions()
+	}
+	maxLen, overlap, err := opts.byteLimits()
+	if err != nil {
+		return nil, err
+	}
+
+	var chunks []Chunk
+	for _, sec := range splitSections(markdown) {
+		for _, r := range splitRange(markdown, sec.start, sec.end, maxLen, overlap) {
+			text := markdown[r.start:r.end]
+			chunks = append(chunks, Chunk{
+				Index:       len(chunks),
+				Text:        text,
+				HeadingPath: sec.path,
+				SourceURL:   sourceURL,
+				Offset:      r.start,
+				Length:      len(text),
+				Tokens:      EstimateTokens(text),
+			})
+		}
+	}
+
+	return chunks, nil
+}
+
+// Validate reports whether the options describe a usable chunk budget
+func (o *Options) Validate() error {
+	_, _, err := o.byteLimits()
+	return err
+}
+
+// byteLimits converts the configured budget into byte lengths
+func (o *Options) byteLimits() (int, int, error) {
+	if o.Size <= 0 {
+		return 0, 0, fmt.Errorf("chunk size must be positive, got %d", o.Size)
+	}
+	if o.Overlap < 0 || o.Overlap >= o.Size {
+		return 0, 0, fmt.Errorf("chunk overlap must be between 0 and size, got %d", o.Overlap)
+	}
+
+	var maxLen, overlap int
+	switch o.Unit {
+	case Tokens, "":
+		maxLen, overlap = o.Size*CharsPerToken, o.Overlap*CharsPerToken
+	case Chars:
+		maxLen, overlap = o.Size, o.Overlap
+	default:
+		return 0, 0, fmt.Errorf("unknown chunk unit %q", o.Unit)
+	}
+	if maxLen < minChunkBytes {
+		return 0, 0, fmt.Errorf("chunk size must be at least %d chars (%d tokens), got %d %s",
+			minChunkBytes, minChunkBytes/CharsPerToken, o.Size, o.Unit)
+	}
+	return maxLen, overlap, nil
+}
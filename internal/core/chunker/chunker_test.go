@@ -0,0 +1,76 @@
+package chunker_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ncecere/reader-go/internal/core/chunker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitHeadings(t *testing.T) {
+	markdown := "Intro text.\n\n" +
+		"# Guide\n\n" +
+		"## Install\n\nRun the installer.\n\n" +
+		"```sh\n# not a heading\nmake\n```\n\n" +
+		"## Usage\n\nCall the API.\n\n" +
+		"# Appendix\n\nMore.\n"
+
+	chunks, err := chunker.Split(markdown, "https://example.com", &chunker.Options{Size: 1000, Unit: chunker.Chars})
+	require.NoError(t, err)
+	require.Len(t, chunks, 4)
+
+	assert.Empty(t, chunks[0].HeadingPath)
+	assert.Equal(t, []string{"Guide", "Install"}, chunks[1].HeadingPath)
+	assert.Contains(t, chunks[1].Text, "# Guide")
+	assert.Contains(t, chunks[1].Text, "# not a heading")
+	assert.Equal(t, []string{"Guide", "Usage"}, chunks[2].HeadingPath)
+	assert.Equal(t, []string{"Appendix"}, chunks[3].HeadingPath)
+
+	for i, c := range chunks {
+		assert.Equal(t, i, c.Index)
+		assert.Equal(t, "https://example.com", c.SourceURL)
+		assert.Equal(t, markdown[c.Offset:c.Offset+c.Length], c.Text)
+	}
+}
+
+func TestSplitLongSection(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("# Long\n\n")
+	for i := 0; i < 40; i++ {
+		b.WriteString("Lorem ipsum dolor sit amet, consectetur adipiscing elit.\n\n")
+	}
+	markdown := b.String()
+
+	chunks, err := chunker.Split(markdown, "", &chunker.Options{Size: 300, Overlap: 40, Unit: chunker.Chars})
+	require.NoError(t, err)
+	require.Greater(t, len(chunks), 5)
+
+	for i, c := range chunks {
+		assert.LessOrEqual(t, c.Length, 300)
+		assert.Equal(t, []string{"Long"}, c.HeadingPath)
+		assert.Equal(t, markdown[c.Offset:c.Offset+c.Length], c.Text)
+		if i > 0 {
+			prev := chunks[i-1]
+			assert.Less(t, c.Offset, prev.Offset+prev.Length, "chunks should overlap")
+			assert.Greater(t, c.Offset, prev.Offset)
+		}
+	}
+	last := chunks[len(chunks)-1]
+	assert.Equal(t, len(markdown), last.Offset+last.Length)
+}
+
+func TestSplitInvalidOptions(t *testing.T) {
+	_, err := chunker.Split("text", "", &chunker.Options{Size: 10, Overlap: 10})
+	assert.Error(t, err)
+
+	_, err = chunker.Split("text", "", &chunker.Options{Size: 10, Unit: "words"})
+	assert.Error(t, err)
+
+	_, err = chunker.Split("a\n\nbc", "", &chunker.Options{Size: 1, Unit: chunker.Chars})
+	assert.ErrorContains(t, err, "at least 16 chars")
+
+	_, err = chunker.Split("a\n\nbc", "", &chunker.Options{Size: 3, Unit: chunker.Tokens})
+	assert.ErrorContains(t, err, "at least 16 chars")
+}
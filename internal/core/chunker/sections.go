@@ -0,0 +1,95 @@
+package chunker
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	headingLine = regexp.MustCompile(`^(#{1,6})[ \t]+(.+?)[ \t#]*$`)
+	fenceLine   = regexp.MustCompile("^[ \t]*(```+|~~~+)")
+)
+
+// section is a heading and the content below it up to the next heading
+type section struct {
+	start, end int
+	path       []string
+}
+
+// splitSections cuts markdown at ATX headings outside fenced code blocks.
+// Sections that contain nothing but their heading are folded into the next section.
+func splitSections(markdown string) []section {
+	var (
+		sections []section
+		stack    []string
+		fence    string
+		start    int
+		path     []string
+	)
+
+	flush := func(end int) {
+		if strings.TrimSpace(markdown[start:end]) != "" {
+			sections = append(sections, section{start: start, end: end, path: path})
+		}
+	}
+
+	for offset := 0; offset < len(markdown); {
+		lineEnd := strings.IndexByte(markdown[offset:], '\n')
+		if lineEnd < 0 {
+			lineEnd = len(markdown)
+		} else {
+			lineEnd += offset + 1
+		}
+		line := strings.TrimRight(markdown[offset:lineEnd], "\r\n")
+
+		if m := fenceLine.FindStringSubmatch(line); m != nil {
+			switch {
+			case fence == "":
+				fence = m[1]
+			case strings.HasPrefix(m[1], fence) && strings.TrimSpace(line) == m[1]:
+				fence = ""
+			}
+		} else if m := headingLine.FindStringSubmatch(line); m != nil && fence == "" {
+			if !headingOnly(markdown[start:offset]) {
+				flush(offset)
+				start = offset
+			}
+			level := len(m[1])
+			if len(stack) >= level {
+				stack = stack[:level-1]
+			}
+			for len(stack) < level-1 {
+				stack = append(stack, "")
+			}
+			stack = append(stack, m[2])
+			path = compactPath(stack)
+		}
+
+		offset = lineEnd
+	}
+	flush(len(markdown))
+
+	return sections
+}
+
+// headingOnly reports whether text consists solely of heading lines and whitespace
+func headingOnly(text string) bool {
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !headingLine.MatchString(line) {
+			return false
+		}
+	}
+	return true
+}
+
+// compactPath copies the heading stack, dropping levels that were skipped
+func compactPath(stack []string) []string {
+	path := make([]string, 0, len(stack))
+	for _, h := range stack {
+		if h != "" {
+			path = append(path, h)
+		}
+	}
+	return path
+}
@@ -0,0 +1,123 @@
+package chunker
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// span is a half-open byte range of the source markdown
+type span struct {
+	start, end int
+}
+
+// splitRange cuts text[start:end] into spans of at most maxLen bytes.
+// Each span after the first begins up to overlap bytes before the previous one ended.
+func splitRange(text string, start, end, maxLen, overlap int) []span {
+	if end-start <= maxLen {
+		return []span{{start, end}}
+	}
+
+	boundaries := blockBoundaries(text, start, end)
+
+	var spans []span
+	for pos := start; pos < end; {
+		limit := pos + maxLen
+		if limit >= end {
+			spans = append(spans, span{pos, end})
+			break
+		}
+
+		cut := bestCut(text, boundaries, pos, limit, maxLen)
+		if cut <= pos {
+			// Never emit an empty span, which would not advance pos
+			cut = limit
+		}
+		spans = append(spans, span{pos, cut})
+
+		next := cut
+		if overlap > 0 {
+			next = alignToWord(text, cut-overlap, cut)
+		}
+		if next <= pos {
+			next = cut
+		}
+		if next == cut {
+			for next < end && text[next] == '\n' {
+				next++
+			}
+		}
+		pos = next
+	}
+
+	return spans
+}
+
+// bestCut picks where to end a span starting at pos: a paragraph break, then a line
+// break, then a space, falling back to the last rune boundary before limit. The cut
+// is always after pos.
+func bestCut(text string, boundaries []int, pos, limit, maxLen int) int {
+	minCut := pos + max(1, maxLen/2)
+
+	for i := len(boundaries) - 1; i >= 0; i-- {
+		if b := boundaries[i]; b <= limit && b >= minCut {
+			return b
+		}
+	}
+	if i := strings.LastIndexByte(text[minCut:limit], '\n'); i >= 0 {
+		return minCut + i + 1
+	}
+	if i := strings.LastIndexAny(text[minCut:limit], " \t"); i >= 0 {
+		return minCut + i + 1
+	}
+
+	cut := limit
+	for cut > pos+1 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	// A rune longer than the span is kept whole
+	for cut < len(text) && !utf8.RuneStart(text[cut]) {
+		cut++
+	}
+	return cut
+}
+
+// blockBoundaries returns offsets just after blank lines that are not inside fenced code
+func blockBoundaries(text string, start, end int) []int {
+	var boundaries []int
+	inFence := false
+
+	for offset := start; offset < end; {
+		lineEnd := strings.IndexByte(text[offset:end], '\n')
+		if lineEnd < 0 {
+			break
+		}
+		lineEnd += offset + 1
+		line := strings.TrimSpace(text[offset:lineEnd])
+
+		if fenceLine.MatchString(line) {
+			inFence = !inFence
+			if !inFence {
+				boundaries = append(boundaries, lineEnd)
+			}
+		} else if line == "" && !inFence {
+			boundaries = append(boundaries, lineEnd)
+		}
+		offset = lineEnd
+	}
+
+	return boundaries
+}
+
+// alignToWord moves offset forward to the start of the next word, staying before limit
+func alignToWord(text string, offset, limit int) int {
+	if offset <= 0 {
+		return 0
+	}
+	if i := strings.IndexAny(text[offset:limit], " \t\n"); i >= 0 {
+		return offset + i + 1
+	}
+	for offset < limit && !utf8.RuneStart(text[offset]) {
+		offset++
+	}
+	return offset
+}
@@ -0,0 +1,34 @@
+package chunker
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitRangeTinySpans(t *testing.T) {
+	tests := []string{"a\n\nbc", "ab cd\nef", "héllo wörld"}
+
+	for _, text := range tests {
+		done := make(chan []span, 1)
+		go func() { done <- splitRange(text, 0, len(text), 1, 0) }()
+
+		select {
+		case spans := <-done:
+			var joined strings.Builder
+			pos := 0
+			for _, s := range spans {
+				require.Greater(t, s.end, s.start, "spans are never empty")
+				assert.GreaterOrEqual(t, s.start, pos)
+				joined.WriteString(text[pos:s.end])
+				pos = s.end
+			}
+			assert.Equal(t, text, joined.String())
+		case <-time.After(2 * time.Second):
+			t.Fatalf("splitRange(%q) with one byte spans did not finish", text)
+		}
+	}
+}
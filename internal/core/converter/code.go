@@ -0,0 +1,157 @@
+package converter
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	md "github.com/JohannesKaufmann/html-to-markdown"
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// langAttr carries the detected language from the before hook to the pre rule
+const langAttr = "data-reader-lang"
+
+// codeContainers are elements that wrap code samples in common highlighters
+const codeContainers = "pre, .highlight, .highlighter-rouge, .codehilite, .code-block, " +
+	".code-toolbar, .codeblock, .sourceCode, div[data-lang], div[data-language], figure"
+
+// codeNoise matches line-number gutters, copy buttons and other chrome inside code samples
+const codeNoise = ".linenos, .lineno, .line-numbers-rows, .line-number, .gutter, .hljs-ln-numbers, " +
+	".react-syntax-highlighter-line-number, .blob-num, .copy, .copy-button, .copy-code-button, " +
+	".clipboard, .btn-clipboard, .code-copy, button, .sr-only, .visually-hidden"
+
+var (
+	// languageClass matches class names that name the code language
+	languageClass = regexp.MustCompile(`^(?:language|lang|highlight-source|highlight|code)-([A-Za-z0-9+#_.-]+)$`)
+	// languageChars restricts info strings to characters that are safe in a fence
+	languageChars = regexp.MustCompile(`[^a-z0-9+#_.-]`)
+)
+
+// ignoredLanguages are class suffixes that do not name a real language
+var ignoredLanguages = map[string]bool{
+	"": true, "none": true, "plain": true, "plaintext": true, "text": true, "nohighlight": true,
+	"source": true, "block": true, "line": true, "toolbar": true, "notranslate": true, "default": true,
+}
+
+// normalizeCodeBlocks strips gutters and copy buttons from code samples, unwraps
+// table-based highlighters and tags every <pre> with its detected language
+func normalizeCodeBlocks(selec *goquery.Selection) {
+	// Table layouts put line numbers and code in separate cells
+	selec.Find("table.highlighttable, table.rouge-table, table.hljs-ln").Each(func(_ int, table *goquery.Selection) {
+		code := table.Find("td.code, td.rouge-code, td.hljs-ln-code")
+		if code.Length() == 0 {
+			return
+		}
+		if code.Find("pre").Length() > 0 {
+			table.ReplaceWithSelection(code.Find("pre").First())
+			return
+		}
+		lines := make([]string, 0, code.Length())
+		code.Each(func(_ int, td *goquery.Selection) { lines = append(lines, td.Text()) })
+		table.ReplaceWithHtml("<pre><code>" + html.EscapeString(strings.Join(lines, "\n")) + "</code></pre>")
+	})
+
+	// Only containers holding a <pre> are code samples; figures and highlights are also
+	// used for images and widgets, whose buttons and screen reader text are content
+	selec.Find(codeContainers).FilterFunction(func(_ int, s *goquery.Selection) bool {
+		return s.Is("pre") || s.Find("pre").Length() > 0
+	}).Find(codeNoise).Remove()
+
+	selec.Find("pre").Each(func(_ int, pre *goquery.Selection) {
+		if lang := detectLanguage(pre); lang != "" {
+			pre.SetAttr(langAttr, lang)
+		}
+	})
+}
+
+// detectLanguage checks the <code> child, the <pre> itself and a few ancestors for
+// class names or data attributes that name the language
+func detectLanguage(pre *goquery.Selection) string {
+	candidates := []*goquery.Selection{pre.ChildrenFiltered("code").First(), pre}
+	for parent, depth := pre.Parent(), 0; parent.Length() > 0 && depth < 3; parent, depth = parent.Parent(), depth+1 {
+		candidates = append(candidates, parent)
+	}
+
+	for i, s := range candidates {
+		if s.Length() == 0 {
+			continue
+		}
+		attrs := []string{"data-lang", "data-language"}
+		if i < 2 {
+			// GitHub renders <pre lang="go">; on ancestors lang is the human language
+			attrs = append(attrs, "lang")
+		}
+		for _, attr := range attrs {
+			if lang := cleanLanguage(s.AttrOr(attr, "")); lang != "" {
+				return lang
+			}
+		}
+		classes := strings.Fields(s.AttrOr("class", ""))
+		for j, class := range classes {
+			if m := languageClass.FindStringSubmatch(class); m != nil {
+				if lang := cleanLanguage(m[1]); lang != "" {
+					return lang
+				}
+			}
+			// Pandoc writes class="sourceCode python", SyntaxHighlighter class="brush: js"
+			if (class == "sourceCode" || class == "brush:") && j+1 < len(classes) {
+				return cleanLanguage(classes[j+1])
+			}
+		}
+	}
+	return ""
+}
+
+func cleanLanguage(lang string) string {
+	lang = languageChars.ReplaceAllString(strings.ToLower(strings.TrimSpace(lang)), "")
+	if ignoredLanguages[lang] {
+		return ""
+	}
+	return lang
+}
+
+// codeBlockRule emits fenced code blocks using the language detected by normalizeCodeBlocks
+func codeBlockRule() md.Rule {
+	return md.Rule{
+		Filter: []string{"pre"},
+		Replacement: func(_ string, selec *goquery.Selection, opt *md.Options) *string {
+			code := strings.Trim(codeText(selec), "\n")
+
+			fenceChar, _ := utf8.DecodeRuneInString(opt.Fence)
+			fence := md.CalculateCodeFence(fenceChar, code)
+
+			text := "\n\n" + fence + selec.AttrOr(langAttr, "") + "\n" +
+				code +
+				"\n" + fence + "\n\n"
+			return &text
+		},
+	}
+}
+
+// codeText flattens highlighted markup, turning <br> and per-line block elements into newlines
+func codeText(selec *goquery.Selection) string {
+	var b strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		switch {
+		case n.Type == html.TextNode:
+			b.WriteString(n.Data)
+			return
+		case n.Type == html.ElementNode && n.Data == "br":
+			b.WriteString("\n")
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+		if n.Type == html.ElementNode && (n.Data == "div" || n.Data == "p") && !strings.HasSuffix(b.String(), "\n") {
+			b.WriteString("\n")
+		}
+	}
+	for _, n := range selec.Nodes {
+		walk(n)
+	}
+	return b.String()
+}
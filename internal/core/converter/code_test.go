@@ -0,0 +1,74 @@
+package converter_test
+
+import (
+	"testing"
+
+	"github.com/ncecere/reader-go/internal/core/converter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTMLToMarkdownCodeBlocks(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{
+			name: "Prism language class",
+			html: `<pre class="language-go"><code class="language-go"><span class="token keyword">func</span> main() {}</code></pre>`,
+			want: "```go\nfunc main() {}\n```",
+		},
+		{
+			name: "GitHub highlight wrapper with copy button",
+			html: `<div class="highlight highlight-source-python"><pre><span class="k">print</span>(1)</pre>` +
+				`<clipboard-copy class="clipboard">Copy</clipboard-copy><button>Copy</button></div>`,
+			want: "```python\nprint(1)\n```",
+		},
+		{
+			name: "data-lang attribute and line breaks",
+			html: `<div data-lang="bash"><pre><code>echo a<br>echo b</code></pre></div>`,
+			want: "```bash\necho a\necho b\n```",
+		},
+		{
+			name: "Pygments table with line numbers",
+			html: `<div class="highlight-ruby notranslate"><table class="highlighttable"><tr>` +
+				"<td class=\"linenos\"><pre>1\n2</pre></td>" +
+				"<td class=\"code\"><pre>puts 1\nputs 2</pre></td></tr></table></div>",
+			want: "```ruby\nputs 1\nputs 2\n```",
+		},
+		{
+			name: "Per-line div markup and gutter spans",
+			html: `<pre class="sourceCode js"><code><div class="line"><span class="line-number">1</span>a();</div>` +
+				`<div class="line"><span class="line-number">2</span>b();</div></code></pre>`,
+			want: "```js\na();\nb();\n```",
+		},
+		{
+			name: "Fence inside code",
+			html: "<pre><code class=\"language-markdown\">```go\nx\n```</code></pre>",
+			want: "````markdown\n```go\nx\n```\n````",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := converter.HTMLToMarkdown("<html><body>" + tt.html + "</body></html>")
+			require.NoError(t, err)
+			assert.Contains(t, got, tt.want)
+			assert.NotContains(t, got, "Copy")
+		})
+	}
+}
+
+func TestHTMLToMarkdownKeepsFigureContent(t *testing.T) {
+	got, err := converter.HTMLToMarkdown(`<html><body>` +
+		`<figure><img src="chart.png" alt="Chart"><figcaption>Revenue <span class="sr-only">in euros</span></figcaption></figure>` +
+		`<figure><button>Play demo</button></figure>` +
+		`<figure><pre><code class="language-go">x := 1</code></pre><button>Copy</button></figure>` +
+		`</body></html>`)
+	require.NoError(t, err)
+	assert.Contains(t, got, "in euros", "image figures keep their screen reader text")
+	assert.Contains(t, got, "Play demo", "widget figures keep their buttons")
+	assert.Contains(t, got, "```go\nx := 1\n```")
+	assert.NotContains(t, got, "Copy")
+}
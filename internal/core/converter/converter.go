@@ -2,15 +2,37 @@ package converter
 
 import (
 	"fmt"
-	"regexp"
+	"strings"
 	"time"
 
 	md "github.com/JohannesKaufmann/html-to-markdown"
 	"github.com/JohannesKaufmann/html-to-markdown/plugin"
+	"github.com/ncecere/reader-go/internal/core/metadata"
 )
 
 // HTMLToMarkdown converts HTML content to Markdown format
 func HTMLToMarkdown(html string) (string, error) {
+	markdown, err := ConvertHTML(html)
+	if err != nil {
+		return "", err
+	}
+
+	// Extract page metadata from the DOM
+	meta, err := metadata.Extract(html)
+	if err != nil {
+		return "", err
+	}
+
+	// Add metadata
+	header := MetadataHeader(meta) +
+		"Visited: " + getCurrentTime() + "\n\n" +
+		"Markdown Content:\n"
+
+	return header + markdown, nil
+}
+
+// ConvertHTML converts HTML content to Markdown without the metadata header
+func ConvertHTML(html string) (string, error) {
 	converter := md.NewConverter("", true, nil)
 
 	// Add GitHub Flavored Markdown plugin
@@ -19,26 +41,49 @@ func HTMLToMarkdown(html string) (string, error) {
 	// Add table support
 	converter.Use(plugin.Table())
 
+	// Preserve code samples with their language annotations
+	converter.Before(normalizeCodeBlocks)
+	converter.AddRules(codeBlockRule())
+
 	// Convert to markdown
-	markdown, err := converter.ConvertString(html)
-	if err != nil {
-		return "", err
-	}
+	return converter.ConvertString(html)
+}
 
-	// Extract title using regex
-	titleRegex := regexp.MustCompile(`<title[^>]*>([^<]+)</title>`)
-	matches := titleRegex.FindStringSubmatch(html)
+// MetadataHeader formats page metadata as "Key: value" lines.
+// The title is always present; other fields are only included when found.
+func MetadataHeader(meta *metadata.Metadata) string {
 	title := "Untitled"
-	if len(matches) > 1 {
-		title = matches[1]
+	if meta != nil && meta.Title != "" {
+		title = meta.Title
 	}
 
-	// Add metadata
-	metadata := "Title: " + title + "\n" +
-		"Visited: " + getCurrentTime() + "\n\n" +
-		"Markdown Content:\n"
+	var b strings.Builder
+	b.WriteString("Title: " + title + "\n")
+	if meta == nil {
+		return b.String()
+	}
+
+	fields := []struct {
+		label string
+		value string
+	}{
+		{"URL Source", meta.Canonical},
+		{"Description", meta.Description},
+		{"Author", meta.Author},
+		{"Site Name", meta.SiteName},
+		{"Language", meta.Lang},
+		{"Published Time", meta.Published},
+		{"Modified Time", meta.Modified},
+		{"Keywords", strings.Join(meta.Keywords, ", ")},
+		{"Image", meta.Image},
+	}
+	for _, f := range fields {
+		if f.value != "" {
+			b.WriteString(f.label + ": " + f.value + "\n")
+		}
+	}
 
-	return metadata + markdown, nil
+	return b.String()
 }
 
 // getCurrentTime returns the current time in a readable format
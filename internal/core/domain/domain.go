@@ -0,0 +1,38 @@
+// Package domain matches hosts against configured domains, which also cover their subdomains
+package domain
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Normalize lowercases a domain and strips a leading "*." or "."
+func Normalize(domain string) string {
+	domain = strings.ToLower(strings.TrimSpace(domain))
+	domain = strings.TrimPrefix(domain, "*.")
+	return strings.Trim(domain, ".")
+}
+
+// Hostname returns the lowercased host of a URL, or "" if it has none
+func Hostname(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// Parents returns host followed by its parent domains, most specific first:
+// "a.b.com" gives "a.b.com", "b.com", "com". It returns nil for an empty host.
+func Parents(host string) []string {
+	if host == "" {
+		return nil
+	}
+	domains := []string{host}
+	for i := 0; i < len(host); i++ {
+		if host[i] == '.' {
+			domains = append(domains, host[i+1:])
+		}
+	}
+	return domains
+}
@@ -0,0 +1,24 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/ncecere/reader-go/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t, "example.com", domain.Normalize(" *.Example.COM "))
+	assert.Equal(t, "example.com", domain.Normalize(".example.com."))
+	assert.Empty(t, domain.Normalize("*."))
+}
+
+func TestHostname(t *testing.T) {
+	assert.Equal(t, "shop.example.de", domain.Hostname("https://Shop.Example.de:8443/cart"))
+	assert.Empty(t, domain.Hostname("::not a url"))
+}
+
+func TestParents(t *testing.T) {
+	assert.Equal(t, []string{"a.b.com", "b.com", "com"}, domain.Parents("a.b.com"))
+	assert.Nil(t, domain.Parents(""))
+}
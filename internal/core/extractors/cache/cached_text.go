@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/ncecere/reader-go/internal/common/logger"
+	"github.com/ncecere/reader-go/internal/core/browser"
 	"github.com/ncecere/reader-go/internal/core/cache"
 	"github.com/ncecere/reader-go/internal/core/extractors"
 	"github.com/ncecere/reader-go/internal/core/metrics"
@@ -39,7 +40,11 @@ func NewCachedTextExtractor(extractor *extractors.TextExtractor, opts *cache.Opt
 
 // ExtractText attempts to get text from cache before falling back to actual extraction
 func (e *CachedTextExtractor) ExtractText(ctx context.Context, url string) (string, error) {
-	key := e.generateKey(url)
+	if !cacheable(ctx) {
+		return e.extractor.ExtractText(ctx, url)
+	}
+
+	key := e.generateKey(url, browser.AutoScrollRequested(ctx))
 
 	if content, found := e.cache.Get(key); found {
 		logger.Log.Info("Cache hit",
@@ -65,7 +70,30 @@ func (e *CachedTextExtractor) ExtractText(ctx context.Context, url string) (stri
 	return content, nil
 }
 
-func (e *CachedTextExtractor) generateKey(url string) string {
+// ExtractTextAndHTML extracts text and HTML from one page load. Only the HTML of a
+// fresh load is available, so the cache is not read, but the text is stored in it.
+func (e *CachedTextExtractor) ExtractTextAndHTML(ctx context.Context, url string) (string, string, error) {
+	text, html, err := e.extractor.ExtractTextAndHTML(ctx, url)
+	if err != nil {
+		return "", "", fmt.Errorf("text extraction failed: %w", err)
+	}
+	if cacheable(ctx) {
+		e.cache.Set(e.generateKey(url, browser.AutoScrollRequested(ctx)), text)
+	}
+	return text, html, nil
+}
+
+// cacheable reports whether text extracted for ctx may be cached. Pages changed by
+// caller actions or seen with the caller's cookies, e.g. after logging in, are not.
+func cacheable(ctx context.Context) bool {
+	return len(browser.ActionsFrom(ctx)) == 0 && browser.SessionFrom(ctx) == nil
+}
+
+// generateKey keys text by URL and whether the page was scrolled, which can load more content
+func (e *CachedTextExtractor) generateKey(url string, scrolled bool) string {
+	if scrolled {
+		url += "\x00scroll"
+	}
 	hash := sha256.Sum256([]byte(url))
 	return hex.EncodeToString(hash[:])
 }
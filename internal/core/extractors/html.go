@@ -24,9 +24,13 @@ func NewHTMLExtractor(pool *browser.Pool) *HTMLExtractor {
 func (e *HTMLExtractor) ExtractHTML(ctx context.Context, url string) (string, error) {
 	logger.Log.Info("Getting HTML content", zap.String("url", url))
 
+	actions := browser.ActionsFrom(ctx)
+	scroll := browser.AutoScrollRequested(ctx)
+	session := browser.SessionFrom(ctx)
+
 	var html string
-	err := e.pool.Execute(ctx, func(ctx context.Context) error {
-		if err := chromedp.Run(ctx, chromedp.Navigate(url)); err != nil {
+	err := e.pool.ExecuteURL(ctx, url, func(ctx context.Context) error {
+		if err := chromedp.Run(ctx, e.pool.Setup(url, session), chromedp.Navigate(url)); err != nil {
 			return fmt.Errorf("failed to navigate: %w", err)
 		}
 
@@ -34,6 +38,26 @@ func (e *HTMLExtractor) ExtractHTML(ctx context.Context, url string) (string, er
 			return fmt.Errorf("failed to wait for page load: %w", err)
 		}
 
+		if err := chromedp.Run(ctx, e.pool.DismissConsent()); err != nil {
+			return fmt.Errorf("failed to dismiss consent dialog: %w", err)
+		}
+
+		if len(actions) > 0 {
+			if err := chromedp.Run(ctx, browser.RunActions(actions)); err != nil {
+				return err
+			}
+		}
+
+		if scroll {
+			if err := chromedp.Run(ctx, e.pool.AutoScroll()); err != nil {
+				return fmt.Errorf("failed to scroll page: %w", err)
+			}
+		}
+
+		if err := chromedp.Run(ctx, browser.ResolveLazyImages()); err != nil {
+			return fmt.Errorf("failed to resolve lazy-loaded images: %w", err)
+		}
+
 		if err := chromedp.Run(ctx, chromedp.OuterHTML("html", &html)); err != nil {
 			return fmt.Errorf("failed to get HTML: %w", err)
 		}
@@ -2,7 +2,9 @@ package extractors
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/chromedp/chromedp"
 	"github.com/ncecere/reader-go/internal/core/browser"
 )
 
@@ -18,12 +20,42 @@ func NewTextExtractor(pool *browser.Pool) *TextExtractor {
 
 // ExtractText extracts text content from a URL
 func (e *TextExtractor) ExtractText(ctx context.Context, url string) (string, error) {
+	text, _, err := e.extract(ctx, url, false)
+	return text, err
+}
+
+// ExtractTextAndHTML extracts text content from a URL along with the HTML of the same
+// page load, so callers needing both do not load the page, and run its actions, twice
+func (e *TextExtractor) ExtractTextAndHTML(ctx context.Context, url string) (string, string, error) {
+	return e.extract(ctx, url, true)
+}
+
+// extract loads url and reads its text and, if withHTML is set, its HTML
+func (e *TextExtractor) extract(ctx context.Context, url string, withHTML bool) (string, string, error) {
+	prepare := []chromedp.Action{e.pool.DismissConsent()}
+	if actions := browser.ActionsFrom(ctx); len(actions) > 0 {
+		prepare = append(prepare, browser.RunActions(actions))
+	}
+	if browser.AutoScrollRequested(ctx) {
+		prepare = append(prepare, e.pool.AutoScroll())
+	}
+
+	var html string
+	if withHTML {
+		prepare = append(prepare, chromedp.OuterHTML("html", &html, chromedp.ByQuery))
+	}
+
+	session := browser.SessionFrom(ctx)
+
 	var extracted string
-	err := e.pool.Execute(ctx, func(ctx context.Context) error {
-		return browser.ExtractTextFromPage(ctx, url, &extracted)
+	err := e.pool.ExecuteURL(ctx, url, func(ctx context.Context) error {
+		if err := chromedp.Run(ctx, e.pool.Setup(url, session)); err != nil {
+			return fmt.Errorf("failed to set up tab: %w", err)
+		}
+		return browser.ExtractTextFromPage(ctx, url, &extracted, prepare...)
 	})
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
-	return extracted, nil
+	return extracted, html, nil
 }
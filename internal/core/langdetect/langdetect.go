@@ -0,0 +1,116 @@
+package langdetect
+
+import (
+	"strings"
+	"unicode"
+)
+
+// minWords is the number of stopword hits needed before a Latin-script guess is trusted
+const minWords = 3
+
+// scripts maps Unicode scripts that (mostly) identify a single language to its code
+var scripts = []struct {
+	table *unicode.RangeTable
+	lang  string
+}{
+	{unicode.Hiragana, "ja"},
+	{unicode.Katakana, "ja"},
+	{unicode.Hangul, "ko"},
+	{unicode.Han, "zh"},
+	{unicode.Cyrillic, "ru"},
+	{unicode.Arabic, "ar"},
+	{unicode.Hebrew, "he"},
+	{unicode.Greek, "el"},
+	{unicode.Thai, "th"},
+	{unicode.Devanagari, "hi"},
+}
+
+// stopwords are frequent function words that distinguish common Latin-script languages
+var stopwords = map[string][]string{
+	"en": {"the", "and", "of", "to", "is", "that", "with", "for", "this", "are"},
+	"de": {"der", "die", "und", "das", "ist", "nicht", "mit", "ein", "eine", "auf"},
+	"fr": {"le", "la", "les", "et", "est", "une", "des", "pour", "dans", "que"},
+	"es": {"el", "los", "las", "y", "es", "una", "por", "para", "con", "que"},
+	"it": {"il", "di", "che", "è", "per", "una", "sono", "della", "con", "gli"},
+	"pt": {"o", "os", "e", "do", "da", "não", "uma", "para", "com", "que"},
+	"nl": {"de", "het", "een", "en", "van", "is", "niet", "op", "met", "zijn"},
+}
+
+// Detect guesses the ISO 639-1 code of the language text is written in.
+// It returns "" when the text gives too little signal.
+func Detect(text string) string {
+	if lang := detectScript(text); lang != "" {
+		return lang
+	}
+	return detectStopwords(text)
+}
+
+// Normalize reduces a language tag such as "en-US" or "pt_BR" to its lowercase primary subtag
+func Normalize(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	return tag
+}
+
+// detectScript returns the language of the dominant non-Latin script, if any.
+// Japanese text mixes Han with kana, so any kana wins over Han.
+func detectScript(text string) string {
+	counts := make(map[string]int)
+	letters := 0
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		for _, s := range scripts {
+			if unicode.Is(s.table, r) {
+				counts[s.lang]++
+				break
+			}
+		}
+	}
+	if letters == 0 {
+		return ""
+	}
+
+	if counts["ja"] > 0 && counts["ja"]+counts["zh"] > letters/2 {
+		return "ja"
+	}
+	best, bestCount := "", 0
+	for lang, n := range counts {
+		if n > bestCount || (n == bestCount && lang < best) {
+			best, bestCount = lang, n
+		}
+	}
+	if bestCount > letters/2 {
+		return best
+	}
+	return ""
+}
+
+// detectStopwords scores Latin-script text by how often each language's stopwords occur
+func detectStopwords(text string) string {
+	freq := make(map[string]int)
+	for _, w := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	}) {
+		freq[w]++
+	}
+
+	best, bestScore := "", 0
+	for lang, words := range stopwords {
+		score := 0
+		for _, w := range words {
+			score += freq[w]
+		}
+		if score > bestScore || (score == bestScore && lang < best) {
+			best, bestScore = lang, score
+		}
+	}
+	if bestScore < minWords {
+		return ""
+	}
+	return best
+}
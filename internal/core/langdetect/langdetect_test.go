@@ -0,0 +1,37 @@
+package langdetect_test
+
+import (
+	"testing"
+
+	"github.com/ncecere/reader-go/internal/core/langdetect"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"The quick brown fox jumps over the lazy dog, and this is the end of the story.", "en"},
+		{"Der schnelle braune Fuchs springt über den faulen Hund, und das ist nicht das Ende.", "de"},
+		{"Le renard brun rapide saute par-dessus le chien paresseux et la fin est proche pour les chiens.", "fr"},
+		{"El rápido zorro marrón salta sobre el perro perezoso y los gatos por la casa con una pelota.", "es"},
+		{"Быстрая коричневая лиса прыгает через ленивую собаку.", "ru"},
+		{"東京は日本の首都です。ここにはたくさんの人が住んでいます。", "ja"},
+		{"北京是中国的首都，也是一座历史悠久的城市。", "zh"},
+		{"서울은 대한민국의 수도입니다.", "ko"},
+		{"Hello world", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, langdetect.Detect(tt.text), tt.text)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t, "en", langdetect.Normalize("en-US"))
+	assert.Equal(t, "pt", langdetect.Normalize(" pt_BR "))
+	assert.Equal(t, "de", langdetect.Normalize("DE"))
+	assert.Equal(t, "", langdetect.Normalize(""))
+}
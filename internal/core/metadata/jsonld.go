@@ -0,0 +1,115 @@
+package metadata
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Article holds the fields of a schema.org Article found in JSON-LD
+type Article struct {
+	Type          string `json:"type,omitempty"`
+	Headline      string `json:"headline,omitempty"`
+	Description   string `json:"description,omitempty"`
+	Author        string `json:"author,omitempty"`
+	Publisher     string `json:"publisher,omitempty"`
+	Image         string `json:"image,omitempty"`
+	DatePublished string `json:"date_published,omitempty"`
+	DateModified  string `json:"date_modified,omitempty"`
+}
+
+// articleTypes lists the schema.org types treated as articles
+var articleTypes = map[string]bool{
+	"article":            true,
+	"newsarticle":        true,
+	"blogposting":        true,
+	"techarticle":        true,
+	"report":             true,
+	"scholarlyarticle":   true,
+	"socialmediaposting": true,
+}
+
+// parseJSONLD decodes every JSON-LD script block, flattening arrays and @graph lists.
+// Malformed blocks are skipped since pages frequently ship broken JSON-LD.
+func parseJSONLD(doc *goquery.Document) []map[string]interface{} {
+	var objects []map[string]interface{}
+	doc.Find(`script[type="application/ld+json"]`).Each(func(_ int, s *goquery.Selection) {
+		var raw interface{}
+		if err := json.Unmarshal([]byte(strings.TrimSpace(s.Text())), &raw); err != nil {
+			return
+		}
+		objects = append(objects, flattenJSONLD(raw)...)
+	})
+	return objects
+}
+
+func flattenJSONLD(raw interface{}) []map[string]interface{} {
+	var objects []map[string]interface{}
+	switch v := raw.(type) {
+	case []interface{}:
+		for _, item := range v {
+			objects = append(objects, flattenJSONLD(item)...)
+		}
+	case map[string]interface{}:
+		if graph, ok := v["@graph"]; ok {
+			objects = append(objects, flattenJSONLD(graph)...)
+			delete(v, "@graph")
+		}
+		if _, ok := v["@type"]; ok {
+			objects = append(objects, v)
+		}
+	}
+	return objects
+}
+
+// findArticle returns the first JSON-LD object typed as an article
+func findArticle(objects []map[string]interface{}) *Article {
+	for _, obj := range objects {
+		for _, t := range stringValues(obj["@type"]) {
+			if !articleTypes[strings.ToLower(t)] {
+				continue
+			}
+			return &Article{
+				Type:          t,
+				Headline:      firstNonEmpty(stringValue(obj["headline"]), stringValue(obj["name"])),
+				Description:   stringValue(obj["description"]),
+				Author:        strings.Join(stringValues(obj["author"]), ", "),
+				Publisher:     stringValue(obj["publisher"]),
+				Image:         stringValue(obj["image"]),
+				DatePublished: stringValue(obj["datePublished"]),
+				DateModified:  stringValue(obj["dateModified"]),
+			}
+		}
+	}
+	return nil
+}
+
+// stringValue returns the first string found in a JSON-LD value
+func stringValue(v interface{}) string {
+	values := stringValues(v)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// stringValues flattens a JSON-LD value into strings. Nested objects such as
+// Person, Organization or ImageObject contribute their name or url.
+func stringValues(v interface{}) []string {
+	switch val := v.(type) {
+	case string:
+		if s := strings.TrimSpace(val); s != "" {
+			return []string{s}
+		}
+	case []interface{}:
+		var out []string
+		for _, item := range val {
+			out = append(out, stringValues(item)...)
+		}
+		return out
+	case map[string]interface{}:
+		return stringValues(firstNonEmpty(stringValue(val["name"]), stringValue(val["url"])))
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package metadata
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Metadata holds the descriptive information found in a page's markup
+type Metadata struct {
+	Title       string                   `json:"title,omitempty"`
+	Description string                   `json:"description,omitempty"`
+	Author      string                   `json:"author,omitempty"`
+	Keywords    []string                 `json:"keywords,omitempty"`
+	Canonical   string                   `json:"canonical,omitempty"`
+	Lang        string                   `json:"lang,omitempty"`
+	SiteName    string                   `json:"site_name,omitempty"`
+	Image       string                   `json:"image,omitempty"`
+	Type        string                   `json:"type,omitempty"`
+	Published   string                   `json:"published,omitempty"`
+	Modified    string                   `json:"modified,omitempty"`
+	OpenGraph   map[string]string        `json:"open_graph,omitempty"`
+	Twitter     map[string]string        `json:"twitter,omitempty"`
+	Meta        map[string]string        `json:"meta,omitempty"`
+	Article     *Article                 `json:"article,omitempty"`
+	JSONLD      []map[string]interface{} `json:"json_ld,omitempty"`
+}
+
+// Extract parses an HTML document and collects its metadata
+func Extract(html string) (*Metadata, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	m := &Metadata{
+		OpenGraph: make(map[string]string),
+		Twitter:   make(map[string]string),
+		Meta:      make(map[string]string),
+	}
+
+	m.collectMetaTags(doc)
+	m.JSONLD = parseJSONLD(doc)
+	m.Article = findArticle(m.JSONLD)
+
+	canonical, _ := doc.Find(`link[rel="canonical"]`).First().Attr("href")
+	lang, _ := doc.Find("html").First().Attr("lang")
+
+	m.Title = firstNonEmpty(strings.TrimSpace(doc.Find("title").First().Text()),
+		m.OpenGraph["title"], m.Twitter["title"], m.article().Headline)
+	m.Description = firstNonEmpty(m.Meta["description"],
+		m.OpenGraph["description"], m.Twitter["description"], m.article().Description)
+	m.Author = firstNonEmpty(m.Meta["author"], m.Meta["article:author"], m.article().Author)
+	m.Canonical = firstNonEmpty(strings.TrimSpace(canonical), m.OpenGraph["url"])
+	m.Lang = firstNonEmpty(strings.TrimSpace(lang), m.Meta["content-language"], m.OpenGraph["locale"])
+	m.SiteName = firstNonEmpty(m.OpenGraph["site_name"], m.article().Publisher)
+	m.Image = firstNonEmpty(m.OpenGraph["image"], m.Twitter["image"], m.article().Image)
+	m.Type = m.OpenGraph["type"]
+	m.Published = firstNonEmpty(m.Meta["article:published_time"], m.Meta["date"],
+		m.Meta["pubdate"], m.Meta["dc.date"], m.article().DatePublished)
+	m.Modified = firstNonEmpty(m.Meta["article:modified_time"], m.OpenGraph["updated_time"],
+		m.Meta["last-modified"], m.article().DateModified)
+	m.Keywords = splitKeywords(m.Meta["keywords"])
+
+	return m, nil
+}
+
+// collectMetaTags sorts <meta> tags into OpenGraph, Twitter and generic buckets.
+// The first occurrence of a key wins, matching how browsers and crawlers read them.
+func (m *Metadata) collectMetaTags(doc *goquery.Document) {
+	doc.Find("meta").Each(func(_ int, s *goquery.Selection) {
+		content, ok := s.Attr("content")
+		if !ok {
+			return
+		}
+		content = strings.TrimSpace(content)
+		if content == "" {
+			return
+		}
+
+		key := strings.ToLower(strings.TrimSpace(firstNonEmpty(
+			s.AttrOr("property", ""), s.AttrOr("name", ""), s.AttrOr("http-equiv", ""))))
+		if key == "" {
+			return
+		}
+
+		switch {
+		case strings.HasPrefix(key, "og:"):
+			setOnce(m.OpenGraph, strings.TrimPrefix(key, "og:"), content)
+		case strings.HasPrefix(key, "twitter:"):
+			setOnce(m.Twitter, strings.TrimPrefix(key, "twitter:"), content)
+		default:
+			setOnce(m.Meta, key, content)
+		}
+	})
+}
+
+// article returns the JSON-LD article or an empty one so lookups never need nil checks
+func (m *Metadata) article() *Article {
+	if m.Article == nil {
+		return &Article{}
+	}
+	return m.Article
+}
+
+func setOnce(dst map[string]string, key, value string) {
+	if _, exists := dst[key]; !exists {
+		dst[key] = value
+	}
+}
+
+func splitKeywords(raw string) []string {
+	if raw == "" {
+		return nil
+	}
+	var keywords []string
+	for _, k := range strings.Split(raw, ",") {
+		if k = strings.TrimSpace(k); k != "" {
+			keywords = append(keywords, k)
+		}
+	}
+	return keywords
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,75 @@
+package metadata_test
+
+import (
+	"testing"
+
+	"github.com/ncecere/reader-go/internal/core/metadata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtract(t *testing.T) {
+	html := `
+		<!DOCTYPE html>
+		<html lang="en-GB">
+		<head>
+			<title data-x="a>b">Fish &amp; Chips</title>
+			<meta name="description" content="A guide to frying">
+			<meta name="keywords" content="fish, chips , ,batter">
+			<meta property="og:title" content="OG Title">
+			<meta property="og:site_name" content="Chippy Times">
+			<meta property="og:image" content="https://example.com/img.png">
+			<meta name="twitter:card" content="summary_large_image">
+			<meta property="article:published_time" content="2024-05-01T10:00:00Z">
+			<link rel="canonical" href="https://example.com/fish">
+			<script type="application/ld+json">
+				{"@context": "https://schema.org", "@graph": [
+					{"@type": "WebSite", "name": "Chippy Times"},
+					{"@type": ["NewsArticle"], "headline": "Frying 101",
+					 "author": [{"@type": "Person", "name": "Jo Cod"}, "Sam Hake"],
+					 "dateModified": "2024-05-02"}
+				]}
+			</script>
+			<script type="application/ld+json">{not json</script>
+		</head>
+		<body><p>Hello</p></body>
+		</html>
+	`
+
+	meta, err := metadata.Extract(html)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Fish & Chips", meta.Title)
+	assert.Equal(t, "A guide to frying", meta.Description)
+	assert.Equal(t, []string{"fish", "chips", "batter"}, meta.Keywords)
+	assert.Equal(t, "https://example.com/fish", meta.Canonical)
+	assert.Equal(t, "en-GB", meta.Lang)
+	assert.Equal(t, "Chippy Times", meta.SiteName)
+	assert.Equal(t, "https://example.com/img.png", meta.Image)
+	assert.Equal(t, "summary_large_image", meta.Twitter["card"])
+	assert.Equal(t, "2024-05-01T10:00:00Z", meta.Published)
+	assert.Equal(t, "2024-05-02", meta.Modified)
+	assert.Equal(t, "Jo Cod, Sam Hake", meta.Author)
+	assert.Len(t, meta.JSONLD, 2)
+
+	require.NotNil(t, meta.Article)
+	assert.Equal(t, "NewsArticle", meta.Article.Type)
+	assert.Equal(t, "Frying 101", meta.Article.Headline)
+}
+
+func TestExtractFallbacks(t *testing.T) {
+	html := `<html><head>
+		<meta property="og:title" content="Only OG">
+		<meta property="og:url" content="https://example.com/og">
+		<meta property="og:description" content="OG description">
+	</head><body></body></html>`
+
+	meta, err := metadata.Extract(html)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Only OG", meta.Title)
+	assert.Equal(t, "https://example.com/og", meta.Canonical)
+	assert.Equal(t, "OG description", meta.Description)
+	assert.Nil(t, meta.Article)
+	assert.Empty(t, meta.Lang)
+}
@@ -0,0 +1,161 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/ncecere/reader-go/internal/common/logger"
+	"go.uber.org/zap"
+	xproxy "golang.org/x/net/proxy"
+)
+
+// bridge is a local HTTP proxy forwarding through a SOCKS5 proxy with credentials.
+// Chrome cannot authenticate to SOCKS5 proxies itself, so it is pointed at the bridge.
+type bridge struct {
+	listener net.Listener
+	server   *http.Server
+}
+
+// ChromeServer returns the proxy server Chrome should use for p: the proxy itself
+// without credentials, or for SOCKS5 proxies with credentials a local bridge that
+// authenticates for it. HTTP proxy credentials are supplied when Chrome is challenged.
+func (p *Proxy) ChromeServer() (string, error) {
+	_, _, auth := p.Credentials()
+	if !p.SOCKS() || !auth {
+		if p.url.Scheme == "socks5h" {
+			// Chrome does not know socks5h, but resolves hosts through SOCKS5 proxies anyway
+			return "socks5://" + p.url.Host, nil
+		}
+		return p.label, nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.bridge == nil {
+		b, err := startBridge(p)
+		if err != nil {
+			return "", err
+		}
+		p.bridge = b
+	}
+	return "http://" + p.bridge.listener.Addr().String(), nil
+}
+
+// closeBridge stops p's bridge, if it has one
+func (p *Proxy) closeBridge() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.bridge != nil {
+		_ = p.bridge.server.Close()
+		p.bridge = nil
+	}
+}
+
+// startBridge listens on a loopback port and forwards requests through p
+func startBridge(p *Proxy) (*bridge, error) {
+	username, password, _ := p.Credentials()
+	address := p.url.Host
+	if p.url.Port() == "" {
+		address = net.JoinHostPort(p.url.Hostname(), "1080")
+	}
+	dialer, err := xproxy.SOCKS5("tcp", address, &xproxy.Auth{User: username, Password: password}, xproxy.Direct)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SOCKS5 dialer for %s: %w", p.label, err)
+	}
+	dial := dialer.(xproxy.ContextDialer).DialContext
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to start bridge for %s: %w", p.label, err)
+	}
+
+	transport := &http.Transport{
+		DialContext:         dial,
+		TLSHandshakeTimeout: 10 * time.Second,
+		IdleConnTimeout:     90 * time.Second,
+	}
+	b := &bridge{
+		listener: listener,
+		server: &http.Server{
+			Handler:           bridgeHandler(dial, transport),
+			ReadHeaderTimeout: 10 * time.Second,
+		},
+	}
+	go func() {
+		if err := b.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			logger.Log.Error("Proxy bridge stopped", zap.String("proxy", p.label), zap.Error(err))
+		}
+	}()
+
+	logger.Log.Info("Started SOCKS5 bridge for Chrome",
+		zap.String("proxy", p.label),
+		zap.String("address", listener.Addr().String()))
+	return b, nil
+}
+
+// bridgeHandler tunnels CONNECT requests and forwards plain HTTP requests through dial
+func bridgeHandler(dial func(ctx context.Context, network, addr string) (net.Conn, error), transport http.RoundTripper) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodConnect {
+			tunnel(w, r, dial)
+			return
+		}
+
+		// Proxy requests carry the absolute URL; forward them as client requests
+		r.RequestURI = ""
+		r.Header.Del("Proxy-Connection")
+		r.Header.Del("Proxy-Authorization")
+		resp, err := transport.RoundTrip(r)
+		if err != nil {
+			http.Error(w, "SOCKS5 proxy request failed: "+err.Error(), http.StatusBadGateway)
+			return
+		}
+		defer resp.Body.Close()
+
+		for name, values := range resp.Header {
+			for _, value := range values {
+				w.Header().Add(name, value)
+			}
+		}
+		w.WriteHeader(resp.StatusCode)
+		_, _ = io.Copy(w, resp.Body)
+	}
+}
+
+// tunnel connects the client to r.Host through dial and copies bytes both ways
+func tunnel(w http.ResponseWriter, r *http.Request, dial func(ctx context.Context, network, addr string) (net.Conn, error)) {
+	upstream, err := dial(r.Context(), "tcp", r.Host)
+	if err != nil {
+		http.Error(w, "SOCKS5 proxy connect failed: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		upstream.Close()
+		http.Error(w, "tunneling not supported", http.StatusInternalServerError)
+		return
+	}
+	client, buffered, err := hijacker.Hijack()
+	if err != nil {
+		upstream.Close()
+		return
+	}
+	if _, err := client.Write([]byte("HTTP/1.1 200 Connection Established\r\n\r\n")); err != nil {
+		client.Close()
+		upstream.Close()
+		return
+	}
+
+	go func() {
+		// Read through the buffer, which may hold bytes sent right after the CONNECT
+		_, _ = io.Copy(upstream, buffered)
+		upstream.Close()
+	}()
+	_, _ = io.Copy(client, upstream)
+	client.Close()
+}
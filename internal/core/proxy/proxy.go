@@ -0,0 +1,330 @@
+package proxy
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ncecere/reader-go/internal/common/config"
+	"github.com/ncecere/reader-go/internal/common/logger"
+	commonmetrics "github.com/ncecere/reader-go/internal/common/metrics"
+	"github.com/ncecere/reader-go/internal/core/domain"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultMaxFailures is used when ProxyConfig.MaxFailures is 0
+	defaultMaxFailures = 3
+	// defaultCooldown is used when ProxyConfig.Cooldown is 0
+	defaultCooldown = 60 * time.Second
+	// maxAttempts bounds how many proxies one request fails over to
+	maxAttempts = 3
+)
+
+// Proxy is one configured proxy and its health
+type Proxy struct {
+	url   *url.URL
+	label string // the URL without credentials, for logs and metrics
+
+	mu        sync.Mutex
+	failures  int // consecutive
+	downUntil time.Time
+	bridge    *bridge
+}
+
+// parse parses a proxy URL; a URL without a scheme is an HTTP proxy
+func parse(raw string) (*Proxy, error) {
+	raw = strings.TrimSpace(raw)
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q (want http, https, socks5 or socks5h)", u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("proxy URL %q has no host", u.Redacted())
+	}
+	return &Proxy{
+		url:   u,
+		label: u.Scheme + "://" + u.Host,
+	}, nil
+}
+
+// URL returns the proxy URL including credentials
+func (p *Proxy) URL() *url.URL {
+	return p.url
+}
+
+// String returns the proxy URL without credentials
+func (p *Proxy) String() string {
+	return p.label
+}
+
+// Credentials returns the user name and password of the proxy, if it has any
+func (p *Proxy) Credentials() (username, password string, ok bool) {
+	if p.url.User == nil {
+		return "", "", false
+	}
+	password, _ = p.url.User.Password()
+	return p.url.User.Username(), password, true
+}
+
+// SOCKS reports whether the proxy speaks SOCKS5
+func (p *Proxy) SOCKS() bool {
+	return p.url.Scheme == "socks5" || p.url.Scheme == "socks5h"
+}
+
+// Manager picks the proxies for outgoing requests and tracks their health
+type Manager struct {
+	client      string // browser or ai, for metrics
+	all         []*Proxy
+	defaults    *group
+	sites       map[string]*group // nil group: connect directly
+	bypass      []string
+	maxFailures int
+	cooldown    time.Duration
+	now         func() time.Time
+}
+
+// group is a pool of proxies rotated through round-robin
+type group struct {
+	proxies []*Proxy
+	next    uint32
+}
+
+// New creates a proxy manager for client from the configuration. It returns nil if no
+// proxies are configured; a nil Manager connects directly.
+func New(cfg config.ProxyConfig, client string) (*Manager, error) {
+	if len(cfg.URLs) == 0 && len(cfg.Sites) == 0 {
+		return nil, nil
+	}
+
+	m := &Manager{
+		client:      client,
+		sites:       make(map[string]*group, len(cfg.Sites)),
+		maxFailures: cfg.MaxFailures,
+		cooldown:    time.Duration(cfg.Cooldown) * time.Second,
+		now:         time.Now,
+	}
+	if m.maxFailures <= 0 {
+		m.maxFailures = defaultMaxFailures
+	}
+	if m.cooldown <= 0 {
+		m.cooldown = defaultCooldown
+	}
+	for _, host := range cfg.Bypass {
+		if host = domain.Normalize(host); host != "" {
+			m.bypass = append(m.bypass, host)
+		}
+	}
+
+	// The same URL in several groups is one proxy with one health record
+	known := make(map[string]*Proxy)
+	newGroup := func(urls []string) (*group, error) {
+		g := &group{}
+		for _, raw := range urls {
+			p, err := parse(raw)
+			if err != nil {
+				return nil, err
+			}
+			if existing, ok := known[p.url.String()]; ok {
+				p = existing
+			} else {
+				known[p.url.String()] = p
+				m.all = append(m.all, p)
+				commonmetrics.ProxyUp.WithLabelValues(client, p.label).Set(1)
+			}
+			g.proxies = append(g.proxies, p)
+		}
+		return g, nil
+	}
+
+	var err error
+	if m.defaults, err = newGroup(cfg.URLs); err != nil {
+		return nil, err
+	}
+	for site, sc := range cfg.Sites {
+		site = domain.Normalize(site)
+		if sc.Direct {
+			m.sites[site] = nil
+			continue
+		}
+		if len(sc.URLs) == 0 {
+			return nil, fmt.Errorf("proxy site %s needs urls or direct", site)
+		}
+		if m.sites[site], err = newGroup(sc.URLs); err != nil {
+			return nil, fmt.Errorf("proxy site %s: %w", site, err)
+		}
+	}
+
+	logger.Log.Info("Proxies configured",
+		zap.String("client", client),
+		zap.Int("proxies", len(m.all)),
+		zap.Int("sites", len(m.sites)))
+	return m, nil
+}
+
+// Route returns the proxies to try for a request to targetURL, in order: proxies in
+// rotation starting with the next one round-robin, then those cooling down, soonest
+// back first, so a request is only refused a proxy if none is configured. An empty
+// route means connecting directly.
+func (m *Manager) Route(targetURL string) []*Proxy {
+	if m == nil {
+		return nil
+	}
+	g := m.groupFor(domain.Hostname(targetURL))
+	if g == nil || len(g.proxies) == 0 {
+		return nil
+	}
+
+	now := m.now()
+	start := int(atomic.AddUint32(&g.next, 1)-1) % len(g.proxies)
+	var up, down []*Proxy
+	for i := range g.proxies {
+		p := g.proxies[(start+i)%len(g.proxies)]
+		if p.downAt(now) {
+			down = append(down, p)
+		} else {
+			up = append(up, p)
+		}
+	}
+	sort.SliceStable(down, func(i, j int) bool {
+		return down[i].until().Before(down[j].until())
+	})
+
+	route := append(up, down...)
+	if len(route) > maxAttempts {
+		route = route[:maxAttempts]
+	}
+	return route
+}
+
+// groupFor returns the proxies for a host: those of the most specific matching site,
+// or the defaults. It returns nil for hosts reached directly.
+func (m *Manager) groupFor(host string) *group {
+	if host == "" {
+		return m.defaults
+	}
+	for _, parent := range domain.Parents(host) {
+		for _, bypass := range m.bypass {
+			if parent == bypass {
+				return nil
+			}
+		}
+	}
+	for _, parent := range domain.Parents(host) {
+		if g, ok := m.sites[parent]; ok {
+			return g
+		}
+	}
+	return m.defaults
+}
+
+// Bypass returns the hosts reached without a proxy
+func (m *Manager) Bypass() []string {
+	if m == nil {
+		return nil
+	}
+	return m.bypass
+}
+
+// Do calls try with each proxy of targetURL's route until it succeeds or fails with an
+// error that failed does not blame on the proxy, recording the proxies' health. try
+// gets nil when the target is reached directly.
+func (m *Manager) Do(targetURL string, try func(*Proxy) error, failed func(error) bool) error {
+	route := m.Route(targetURL)
+	if len(route) == 0 {
+		return try(nil)
+	}
+
+	var err error
+	for _, p := range route {
+		err = try(p)
+		if err == nil {
+			m.Succeeded(p)
+			return nil
+		}
+		if !failed(err) {
+			// The target or the caller failed, which says nothing about the proxy
+			return err
+		}
+		m.Failed(p)
+		logger.Log.Warn("Request through proxy failed",
+			zap.String("client", m.client),
+			zap.String("proxy", p.label),
+			zap.Error(err))
+	}
+	return err
+}
+
+// Succeeded records that a request got through p
+func (m *Manager) Succeeded(p *Proxy) {
+	p.mu.Lock()
+	wasDown := p.failures >= m.maxFailures
+	p.failures = 0
+	p.downUntil = time.Time{}
+	p.mu.Unlock()
+
+	commonmetrics.ProxyRequests.WithLabelValues(m.client, p.label, "success").Inc()
+	if wasDown {
+		logger.Log.Info("Proxy recovered", zap.String("client", m.client), zap.String("proxy", p.label))
+		commonmetrics.ProxyUp.WithLabelValues(m.client, p.label).Set(1)
+	}
+}
+
+// Failed records that p could not be reached or refused a request. After MaxFailures
+// consecutive failures it leaves the rotation for the cooldown; once back, a single
+// failure takes it out again.
+func (m *Manager) Failed(p *Proxy) {
+	p.mu.Lock()
+	p.failures++
+	down := p.failures >= m.maxFailures
+	if down {
+		p.downUntil = m.now().Add(m.cooldown)
+	}
+	p.mu.Unlock()
+
+	commonmetrics.ProxyRequests.WithLabelValues(m.client, p.label, "failure").Inc()
+	if down {
+		logger.Log.Warn("Proxy taken out of rotation",
+			zap.String("client", m.client),
+			zap.String("proxy", p.label),
+			zap.Duration("cooldown", m.cooldown))
+		commonmetrics.ProxyUp.WithLabelValues(m.client, p.label).Set(0)
+	}
+}
+
+// downAt reports whether p is cooling down at now
+func (p *Proxy) downAt(now time.Time) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return now.Before(p.downUntil)
+}
+
+// until returns when p's cooldown ends
+func (p *Proxy) until() time.Time {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.downUntil
+}
+
+// Close stops the local bridges started for SOCKS5 proxies
+func (m *Manager) Close() {
+	if m == nil {
+		return
+	}
+	for _, p := range m.all {
+		p.closeBridge()
+	}
+}
@@ -0,0 +1,89 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+)
+
+type proxyKey struct{}
+
+// transport sends each request through the proxies of its host, failing over to the
+// next proxy when one cannot be reached
+type transport struct {
+	m    *Manager
+	base *http.Transport
+}
+
+// Transport returns base routed through the manager's proxies. base is modified; its
+// own Proxy setting is replaced. A nil Manager returns base unchanged, connecting directly.
+func (m *Manager) Transport(base *http.Transport) http.RoundTripper {
+	if m == nil {
+		return base
+	}
+	base.Proxy = func(req *http.Request) (*url.URL, error) {
+		if p, ok := req.Context().Value(proxyKey{}).(*Proxy); ok {
+			return p.url, nil
+		}
+		return nil, nil
+	}
+	base.OnProxyConnectResponse = func(_ context.Context, _ *url.URL, _ *http.Request, resp *http.Response) error {
+		if resp.StatusCode == http.StatusProxyAuthRequired {
+			return errProxyAuth
+		}
+		return nil
+	}
+	return &transport{m: m, base: base}
+}
+
+// RoundTrip sends req through the first proxy of its route that can be reached
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var resp *http.Response
+	attempt := 0
+	err := t.m.Do(req.URL.String(), func(p *Proxy) error {
+		r := req
+		if attempt > 0 && req.Body != nil {
+			if req.GetBody == nil {
+				return fmt.Errorf("cannot retry request without GetBody")
+			}
+			body, err := req.GetBody()
+			if err != nil {
+				return err
+			}
+			r = req.Clone(req.Context())
+			r.Body = body
+		}
+		attempt++
+		if p != nil {
+			r = r.WithContext(context.WithValue(r.Context(), proxyKey{}, p))
+		}
+
+		var err error
+		resp, err = t.base.RoundTrip(r)
+		if err == nil && resp.StatusCode == http.StatusProxyAuthRequired {
+			resp.Body.Close()
+			return errProxyAuth
+		}
+		return err
+	}, isProxyError)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// errProxyAuth is returned when a proxy rejects its credentials
+var errProxyAuth = errors.New("proxy authentication required")
+
+// isProxyError reports whether err means the proxy, rather than the target, failed
+func isProxyError(err error) bool {
+	if errors.Is(err, errProxyAuth) {
+		return true
+	}
+	// net/http reports failures to reach the proxy or to set up the tunnel as proxyconnect
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "proxyconnect"
+}
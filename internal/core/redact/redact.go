@@ -0,0 +1,115 @@
+package redact
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ncecere/reader-go/internal/common/config"
+)
+
+// Rule masks every match of Pattern that passes Validate (if set) with Replacement
+type Rule struct {
+	Name        string
+	Pattern     *regexp.Regexp
+	Validate    func(match string) bool
+	Replacement string
+}
+
+// Counts maps rule names to the number of matches masked
+type Counts map[string]int
+
+// Total returns the number of matches masked by all rules
+func (c Counts) Total() int {
+	total := 0
+	for _, n := range c {
+		total += n
+	}
+	return total
+}
+
+// Add adds other's counts to c
+func (c Counts) Add(other Counts) {
+	for name, n := range other {
+		c[name] += n
+	}
+}
+
+// String formats counts as "email=2,phone=1", sorted by rule name
+func (c Counts) String() string {
+	names := make([]string, 0, len(c))
+	for name, n := range c {
+		if n > 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s=%d", name, c[name])
+	}
+	return strings.Join(parts, ",")
+}
+
+// Redactor applies an ordered list of rules
+type Redactor struct {
+	rules []Rule
+}
+
+// New creates a redactor from the named built-in rules (all of them if names is empty)
+// followed by custom regex rules
+func New(names []string, custom []config.RedactionRuleConfig) (*Redactor, error) {
+	var rules []Rule
+	if len(names) == 0 {
+		rules = append(rules, builtins...)
+	} else {
+		enabled := make(map[string]bool, len(names))
+		for _, name := range names {
+			name = strings.ToLower(strings.TrimSpace(name))
+			if _, ok := Builtin(name); !ok {
+				return nil, fmt.Errorf("unknown redaction rule %q (available: %s)", name, strings.Join(BuiltinNames(), ", "))
+			}
+			enabled[name] = true
+		}
+		// Built-in rules keep their own order so that e.g. card numbers are not taken for phone numbers
+		for _, rule := range builtins {
+			if enabled[rule.Name] {
+				rules = append(rules, rule)
+			}
+		}
+	}
+
+	for _, c := range custom {
+		if c.Name == "" {
+			return nil, fmt.Errorf("custom redaction rule with pattern %q has no name", c.Pattern)
+		}
+		pattern, err := regexp.Compile(c.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern for redaction rule %q: %w", c.Name, err)
+		}
+		replacement := c.Replacement
+		if replacement == "" {
+			replacement = "[" + strings.ToUpper(c.Name) + "]"
+		}
+		rules = append(rules, Rule{Name: c.Name, Pattern: pattern, Replacement: replacement})
+	}
+
+	return &Redactor{rules: rules}, nil
+}
+
+// Redact masks sensitive data in text and reports how many matches each rule masked
+func (r *Redactor) Redact(text string) (string, Counts) {
+	counts := make(Counts)
+	for _, rule := range r.rules {
+		text = rule.Pattern.ReplaceAllStringFunc(text, func(match string) string {
+			if rule.Validate != nil && !rule.Validate(match) {
+				return match
+			}
+			counts[rule.Name]++
+			return rule.Replacement
+		})
+	}
+	return text, counts
+}
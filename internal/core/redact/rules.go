@@ -0,0 +1,150 @@
+package redact
+
+import (
+	"math/big"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// builtins are applied in this order. Specific formats run before phone numbers, whose
+// loose pattern would otherwise claim card numbers and IBAN digits.
+var builtins = []Rule{
+	{
+		Name:        "email",
+		Pattern:     regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}`),
+		Replacement: "[EMAIL]",
+	},
+	{
+		Name: "api_key",
+		Pattern: regexp.MustCompile(`\b(?:` +
+			`(?:sk|pk|rk)[-_](?:live_|test_|proj-|ant-)?[A-Za-z0-9_-]{16,}` + // OpenAI, Stripe, Anthropic
+			`|AKIA[0-9A-Z]{16}` + // AWS access key IDs
+			`|gh[pousr]_[A-Za-z0-9]{36,}` + // GitHub tokens
+			`|xox[abprs]-[A-Za-z0-9-]{10,}` + // Slack tokens
+			`|AIza[0-9A-Za-z_-]{35}` + // Google API keys
+			`|eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}` + // JWTs
+			`)`),
+		Replacement: "[API_KEY]",
+	},
+	{
+		Name:        "iban",
+		Pattern:     regexp.MustCompile(`\b[A-Z]{2}\d{2}(?: ?[A-Z0-9]){11,30}\b`),
+		Validate:    validIBAN,
+		Replacement: "[IBAN]",
+	},
+	{
+		Name:        "credit_card",
+		Pattern:     regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`),
+		Validate:    validCard,
+		Replacement: "[CARD]",
+	},
+	{
+		Name:        "ip",
+		Pattern:     regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b|[0-9A-Fa-f]{0,4}(?::[0-9A-Fa-f]{0,4}){2,7}`),
+		Validate:    validIP,
+		Replacement: "[IP]",
+	},
+	{
+		Name:        "phone",
+		Pattern:     regexp.MustCompile(`(?:\+\d{1,3}[ .-]?)?(?:\(\d{1,4}\)[ .-]?)?\b\d{2,4}(?:[ .-]\d{2,4}){1,4}\b`),
+		Validate:    validPhone,
+		Replacement: "[PHONE]",
+	},
+}
+
+// Builtin returns the built-in rule with the given name
+func Builtin(name string) (Rule, bool) {
+	for _, rule := range builtins {
+		if rule.Name == name {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+// BuiltinNames returns the names of the built-in rules in the order they are applied
+func BuiltinNames() []string {
+	names := make([]string, len(builtins))
+	for i, rule := range builtins {
+		names[i] = rule.Name
+	}
+	return names
+}
+
+// digits returns the decimal digits of s
+func digits(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// validCard reports whether s is 13-19 digits passing the Luhn checksum
+func validCard(s string) bool {
+	d := digits(s)
+	if len(d) < 13 || len(d) > 19 || strings.Count(d, d[:1]) == len(d) {
+		return false
+	}
+
+	sum := 0
+	double := false
+	for i := len(d) - 1; i >= 0; i-- {
+		n := int(d[i] - '0')
+		if double {
+			n *= 2
+			if n > 9 {
+				n -= 9
+			}
+		}
+		sum += n
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// validIBAN reports whether s passes the ISO 13616 mod-97 check
+func validIBAN(s string) bool {
+	iban := strings.ReplaceAll(s, " ", "")
+	if len(iban) < 15 || len(iban) > 34 {
+		return false
+	}
+
+	// Move the country code and check digits to the end and turn letters into numbers (A=10)
+	var numeric strings.Builder
+	for _, r := range iban[4:] + iban[:4] {
+		switch {
+		case r >= '0' && r <= '9':
+			numeric.WriteRune(r)
+		case r >= 'A' && r <= 'Z':
+			numeric.WriteString(strconv.Itoa(int(r-'A') + 10))
+		default:
+			return false
+		}
+	}
+
+	n, ok := new(big.Int).SetString(numeric.String(), 10)
+	return ok && new(big.Int).Mod(n, big.NewInt(97)).Int64() == 1
+}
+
+// validIP reports whether s is an IPv4 or IPv6 address. Bare "::" and the like are ignored.
+func validIP(s string) bool {
+	if strings.Contains(s, ":") && len(strings.Trim(s, ":")) == 0 {
+		return false
+	}
+	return net.ParseIP(s) != nil
+}
+
+// validPhone accepts 10-15 digits, or 8-15 with an international prefix, which keeps
+// dates, versions and short numbers out
+func validPhone(s string) bool {
+	n := len(digits(s))
+	if strings.HasPrefix(s, "+") {
+		return n >= 8 && n <= 15
+	}
+	return n >= 10 && n <= 15
+}
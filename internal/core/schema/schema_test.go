@@ -0,0 +1,77 @@
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/ncecere/reader-go/internal/core/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const productSchema = `{
+	"type": "object",
+	"required": ["name", "price"],
+	"additionalProperties": false,
+	"properties": {
+		"name": {"type": "string", "minLength": 1},
+		"price": {"type": "number", "minimum": 0},
+		"currency": {"type": ["string", "null"], "enum": ["USD", "EUR", null]},
+		"sku": {"type": "string", "pattern": "^[A-Z]{3}-\\d+$"},
+		"stock": {"type": "integer"},
+		"tags": {"type": "array", "maxItems": 2, "items": {"type": "string"}}
+	}
+}`
+
+func TestValidateAccepts(t *testing.T) {
+	s, err := schema.Parse([]byte(productSchema))
+	require.NoError(t, err)
+
+	errs, err := s.Validate([]byte(`{"name": "Lamp", "price": 19.5, "currency": null,
+		"sku": "LMP-42", "stock": 3, "tags": ["home"]}`))
+	require.NoError(t, err)
+	assert.Empty(t, errs)
+}
+
+func TestValidateReportsEveryViolation(t *testing.T) {
+	s, err := schema.Parse([]byte(productSchema))
+	require.NoError(t, err)
+
+	errs, err := s.Validate([]byte(`{"name": "", "currency": "GBP", "sku": "lamp",
+		"stock": 1.5, "tags": ["a", "b", 3], "color": "red"}`))
+	require.NoError(t, err)
+
+	var got []string
+	for _, e := range errs {
+		got = append(got, e.Error())
+	}
+	assert.ElementsMatch(t, []string{
+		`$: missing required property "price"`,
+		`$: unexpected property "color"`,
+		`$.name: expected at least 1 characters, got 0`,
+		`$.currency: value is not one of the allowed values`,
+		`$.sku: does not match pattern "^[A-Z]{3}-\\d+$"`,
+		`$.stock: expected [integer], got number`,
+		`$.tags: expected at most 2 items, got 3`,
+		`$.tags[2]: expected [string], got integer`,
+	}, got)
+}
+
+func TestValidateInvalidJSON(t *testing.T) {
+	s, err := schema.Parse([]byte(`{"type": "object"}`))
+	require.NoError(t, err)
+
+	_, err = s.Validate([]byte(`not json`))
+	assert.Error(t, err)
+}
+
+func TestParseRejectsBadSchemas(t *testing.T) {
+	for _, doc := range []string{
+		`not json`,
+		`{"type": "text"}`,
+		`{"type": 5}`,
+		`{"properties": {"a": {"type": "string", "pattern": "("}}}`,
+	} {
+		_, err := schema.Parse([]byte(doc))
+		assert.Error(t, err, doc)
+	}
+}
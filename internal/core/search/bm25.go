@@ -0,0 +1,97 @@
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// BM25 parameters as commonly used by Lucene and Elasticsearch
+const (
+	k1 = 1.2
+	b  = 0.75
+)
+
+// stopwords are common English words that carry no ranking signal
+var stopwords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true,
+	"by": true, "can": true, "do": true, "does": true, "for": true, "from": true, "how": true,
+	"i": true, "in": true, "is": true, "it": true, "of": true, "on": true, "or": true,
+	"that": true, "the": true, "this": true, "to": true, "was": true, "what": true,
+	"when": true, "where": true, "which": true, "who": true, "why": true, "with": true,
+}
+
+// Result is a document index and its relevance score
+type Result struct {
+	Index int
+	Score float64
+}
+
+// Rank scores every document against the query with Okapi BM25 and returns them
+// ordered from most to least relevant. Ties keep document order.
+func Rank(query string, docs []string) []Result {
+	terms := Tokenize(query)
+
+	tokenized := make([][]string, len(docs))
+	docFreq := make(map[string]int)
+	totalLen := 0
+	for i, doc := range docs {
+		tokenized[i] = Tokenize(doc)
+		totalLen += len(tokenized[i])
+
+		seen := make(map[string]bool)
+		for _, t := range tokenized[i] {
+			if !seen[t] {
+				seen[t] = true
+				docFreq[t]++
+			}
+		}
+	}
+
+	avgLen := 1.0
+	if len(docs) > 0 && totalLen > 0 {
+		avgLen = float64(totalLen) / float64(len(docs))
+	}
+
+	results := make([]Result, len(docs))
+	n := float64(len(docs))
+	for i, tokens := range tokenized {
+		freq := make(map[string]int, len(tokens))
+		for _, t := range tokens {
+			freq[t]++
+		}
+
+		score := 0.0
+		for _, term := range terms {
+			tf := float64(freq[term])
+			if tf == 0 {
+				continue
+			}
+			df := float64(docFreq[term])
+			idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+			score += idf * tf * (k1 + 1) / (tf + k1*(1-b+b*float64(len(tokens))/avgLen))
+		}
+		results[i] = Result{Index: i, Score: score}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	return results
+}
+
+// Tokenize lowercases text and splits it into words, dropping stopwords
+func Tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	tokens := fields[:0]
+	for _, f := range fields {
+		if !stopwords[f] {
+			tokens = append(tokens, f)
+		}
+	}
+	return tokens
+}
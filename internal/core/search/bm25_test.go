@@ -0,0 +1,29 @@
+package search_test
+
+import (
+	"testing"
+
+	"github.com/ncecere/reader-go/internal/core/search"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRank(t *testing.T) {
+	docs := []string{
+		"The installation guide covers Linux and macOS.",
+		"Pricing: the Pro plan costs $20 per month, billed monthly.",
+		"Contact support for enterprise pricing and volume discounts on the pro plan.",
+		"Release notes for version 2.0.",
+	}
+
+	results := search.Rank("How much does the Pro plan cost per month?", docs)
+
+	assert.Len(t, results, len(docs))
+	assert.Equal(t, 1, results[0].Index)
+	assert.Equal(t, 2, results[1].Index)
+	assert.Zero(t, results[3].Score)
+}
+
+func TestTokenize(t *testing.T) {
+	assert.Equal(t, []string{"go", "1", "22"}, search.Tokenize("What is Go 1.22?"))
+	assert.Empty(t, search.Tokenize("the and of"))
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/ncecere/reader-go/internal/core/cache"
 	"github.com/ncecere/reader-go/internal/core/extractors"
 	cachex "github.com/ncecere/reader-go/internal/core/extractors/cache"
+	"github.com/ncecere/reader-go/internal/core/metadata"
 	"github.com/ncecere/reader-go/internal/core/metrics"
 	"github.com/ncecere/reader-go/internal/core/parallel"
 	"go.uber.org/zap"
@@ -68,11 +69,40 @@ func (s *Service) GetText(ctx context.Context, url string) (string, error) {
 	return content, err
 }
 
+// GetTextWithMetadata extracts text content and page metadata from a single load of a
+// URL. Metadata that cannot be extracted is logged and returned as nil.
+func (s *Service) GetTextWithMetadata(ctx context.Context, url string) (string, *metadata.Metadata, error) {
+	start := time.Now()
+	content, html, err := s.text.ExtractTextAndHTML(ctx, url)
+	s.metrics.RecordRequest(time.Since(start), err == nil)
+	if err != nil {
+		return "", nil, err
+	}
+
+	meta, err := metadata.Extract(html)
+	if err != nil {
+		logger.Log.Warn("Failed to extract metadata",
+			zap.String("url", url),
+			zap.Error(err))
+		return content, nil, nil
+	}
+	return content, meta, nil
+}
+
 // GetHTML retrieves the HTML content from a URL
 func (s *Service) GetHTML(ctx context.Context, url string) (string, error) {
 	return s.html.ExtractHTML(ctx, url)
 }
 
+// GetMetadata retrieves the HTML of a URL and extracts its page metadata
+func (s *Service) GetMetadata(ctx context.Context, url string) (*metadata.Metadata, error) {
+	html, err := s.html.ExtractHTML(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	return metadata.Extract(html)
+}
+
 // ProcessURLs processes multiple URLs in parallel
 func (s *Service) ProcessURLs(ctx context.Context, urls []string) []parallel.Result {
 	return s.parallel.ProcessURLs(ctx, urls)
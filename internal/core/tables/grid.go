@@ -0,0 +1,123 @@
+package tables
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// maxSpan caps colspan/rowspan values so malformed markup cannot blow up the grid
+const maxSpan = 1000
+
+// pendingCell is a cell that still spans into following rows
+type pendingCell struct {
+	text      string
+	remaining int
+}
+
+// buildGrid expands spanning cells so that each covered position repeats the cell text.
+// Short rows are padded to the widest row.
+func buildGrid(rows []*goquery.Selection) [][]string {
+	var grid [][]string
+	pending := make(map[int]*pendingCell)
+	width := 0
+
+	for _, tr := range rows {
+		var row []string
+		col := 0
+
+		fillPending := func() {
+			for {
+				p, ok := pending[col]
+				if !ok {
+					return
+				}
+				row = append(row, p.text)
+				if p.remaining--; p.remaining == 0 {
+					delete(pending, col)
+				}
+				col++
+			}
+		}
+
+		tr.ChildrenFiltered("th, td").Each(func(_ int, cell *goquery.Selection) {
+			fillPending()
+
+			text := cleanText(cell.Text())
+			colspan := spanAttr(cell, "colspan")
+			rowspan := spanAttr(cell, "rowspan")
+
+			for i := 0; i < colspan; i++ {
+				row = append(row, text)
+				if rowspan > 1 {
+					pending[col] = &pendingCell{text: text, remaining: rowspan - 1}
+				} else {
+					delete(pending, col) // malformed overlap, the explicit cell wins
+				}
+				col++
+			}
+		})
+		fillPending()
+
+		// Cells spanning past the end of this row's own cells still occupy columns
+		for len(pending) > 0 && col < width {
+			if _, ok := pending[col]; !ok {
+				row = append(row, "")
+				col++
+				continue
+			}
+			fillPending()
+		}
+
+		if len(row) > width {
+			width = len(row)
+		}
+		grid = append(grid, row)
+	}
+
+	for i := range grid {
+		for len(grid[i]) < width {
+			grid[i] = append(grid[i], "")
+		}
+	}
+
+	return grid
+}
+
+// mergeHeaders collapses one or more header rows into a single unique label per column
+func mergeHeaders(rows [][]string, width int) []string {
+	headers := make([]string, width)
+	seen := make(map[string]int)
+
+	for col := 0; col < width; col++ {
+		var parts []string
+		for _, row := range rows {
+			if text := row[col]; text != "" && (len(parts) == 0 || parts[len(parts)-1] != text) {
+				parts = append(parts, text)
+			}
+		}
+
+		name := strings.Join(parts, " / ")
+		if name == "" {
+			name = "column_" + strconv.Itoa(col+1)
+		}
+		if seen[name]++; seen[name] > 1 {
+			name += "_" + strconv.Itoa(seen[name])
+		}
+		headers[col] = name
+	}
+
+	return headers
+}
+
+func spanAttr(cell *goquery.Selection, name string) int {
+	n, err := strconv.Atoi(strings.TrimSpace(cell.AttrOr(name, "1")))
+	if err != nil || n < 1 {
+		return 1
+	}
+	if n > maxSpan {
+		return maxSpan
+	}
+	return n
+}
@@ -0,0 +1,69 @@
+package tables
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+)
+
+// jsonTable is the JSON representation of a table with rows keyed by header
+type jsonTable struct {
+	Name    string              `json:"name"`
+	Headers []string            `json:"headers"`
+	Rows    []map[string]string `json:"rows"`
+}
+
+// ToJSON encodes tables as a JSON array where each row is an object keyed by header
+func ToJSON(tables []Table) ([]byte, error) {
+	out := make([]jsonTable, 0, len(tables))
+	for _, t := range tables {
+		rows := make([]map[string]string, 0, len(t.Rows))
+		for _, row := range t.Rows {
+			obj := make(map[string]string, len(t.Headers))
+			for i, header := range t.Headers {
+				obj[header] = row[i]
+			}
+			rows = append(rows, obj)
+		}
+		out = append(out, jsonTable{Name: t.Name, Headers: t.Headers, Rows: rows})
+	}
+
+	data, err := json.Marshal(out)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode tables: %w", err)
+	}
+	return data, nil
+}
+
+// ToCSV encodes a single table as CSV with the headers as the first record
+func ToCSV(t Table) (string, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	if err := w.Write(t.Headers); err != nil {
+		return "", fmt.Errorf("failed to write CSV header: %w", err)
+	}
+	if err := w.WriteAll(t.Rows); err != nil {
+		return "", fmt.Errorf("failed to write CSV rows: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
+// ToCSVDocument encodes all tables as CSV blocks, each preceded by a "Table:" line
+func ToCSVDocument(tables []Table) (string, error) {
+	var buf bytes.Buffer
+	for i, t := range tables {
+		data, err := ToCSV(t)
+		if err != nil {
+			return "", err
+		}
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		buf.WriteString("Table: " + t.Name + "\n")
+		buf.WriteString(data)
+	}
+	return buf.String(), nil
+}
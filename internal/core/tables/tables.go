@@ -0,0 +1,100 @@
+package tables
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Table is a normalized HTML table where every row has one cell per column
+type Table struct {
+	Name    string     `json:"name"`
+	Headers []string   `json:"headers"`
+	Rows    [][]string `json:"-"`
+}
+
+// Extract finds every <table> in an HTML document and normalizes colspan and rowspan
+func Extract(html string) ([]Table, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	var tables []Table
+	doc.Find("table").Each(func(i int, s *goquery.Selection) {
+		headerRows, bodyRows := splitRows(s)
+		grid := buildGrid(append(headerRows, bodyRows...))
+		if len(grid) == 0 {
+			return
+		}
+
+		headerCount := len(headerRows)
+		if headerCount == 0 && allHeaderCells(bodyRows) {
+			headerCount = 1
+		}
+
+		tables = append(tables, Table{
+			Name:    tableName(s, i),
+			Headers: mergeHeaders(grid[:headerCount], len(grid[0])),
+			Rows:    grid[headerCount:],
+		})
+	})
+
+	return tables, nil
+}
+
+// splitRows returns the rows belonging to this table only, skipping nested tables.
+// Rows inside <thead> are treated as header rows.
+func splitRows(table *goquery.Selection) (header, body []*goquery.Selection) {
+	table.Children().Each(func(_ int, section *goquery.Selection) {
+		switch goquery.NodeName(section) {
+		case "thead":
+			section.ChildrenFiltered("tr").Each(func(_ int, tr *goquery.Selection) {
+				header = append(header, tr)
+			})
+		case "tbody", "tfoot":
+			section.ChildrenFiltered("tr").Each(func(_ int, tr *goquery.Selection) {
+				body = append(body, tr)
+			})
+		case "tr":
+			body = append(body, section)
+		}
+	})
+	return header, body
+}
+
+// allHeaderCells reports whether the first row consists solely of <th> cells
+func allHeaderCells(rows []*goquery.Selection) bool {
+	if len(rows) == 0 {
+		return false
+	}
+	cells := rows[0].ChildrenFiltered("th, td")
+	return cells.Length() > 0 && cells.Length() == rows[0].ChildrenFiltered("th").Length()
+}
+
+// tableName uses the caption, then the nearest preceding heading, then a positional name
+func tableName(table *goquery.Selection, index int) string {
+	if caption := cleanText(table.ChildrenFiltered("caption").First().Text()); caption != "" {
+		return caption
+	}
+
+	headings := "h1, h2, h3, h4, h5, h6"
+	for node := table; node.Length() > 0 && goquery.NodeName(node) != "body"; node = node.Parent() {
+		for prev := node.Prev(); prev.Length() > 0; prev = prev.Prev() {
+			if prev.Is(headings) {
+				return cleanText(prev.Text())
+			}
+			if nested := prev.Find(headings).Last(); nested.Length() > 0 {
+				return cleanText(nested.Text())
+			}
+		}
+	}
+
+	return "Table " + strconv.Itoa(index+1)
+}
+
+func cleanText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,74 @@
+package tables_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ncecere/reader-go/internal/core/tables"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtract(t *testing.T) {
+	html := `
+		<html><body>
+		<h2>Pricing</h2>
+		<div>
+			<table>
+				<thead><tr><th>Plan</th><th colspan="2">Price</th></tr></thead>
+				<tbody>
+					<tr><td rowspan="2">Pro</td><td>$10</td><td>monthly</td></tr>
+					<tr><td>$100</td><td>yearly</td></tr>
+					<tr><td>Free</td><td colspan="2">n/a</td></tr>
+				</tbody>
+			</table>
+		</div>
+		<table>
+			<caption>Limits</caption>
+			<tr><td>requests</td><td>1000</td></tr>
+			<tr><td>nested <table><tr><td>inner</td></tr></table></td></tr>
+		</table>
+		</body></html>
+	`
+
+	found, err := tables.Extract(html)
+	require.NoError(t, err)
+	require.Len(t, found, 3)
+
+	pricing := found[0]
+	assert.Equal(t, "Pricing", pricing.Name)
+	assert.Equal(t, []string{"Plan", "Price", "Price_2"}, pricing.Headers)
+	assert.Equal(t, [][]string{
+		{"Pro", "$10", "monthly"},
+		{"Pro", "$100", "yearly"},
+		{"Free", "n/a", "n/a"},
+	}, pricing.Rows)
+
+	limits := found[1]
+	assert.Equal(t, "Limits", limits.Name)
+	assert.Equal(t, []string{"column_1", "column_2"}, limits.Headers)
+	assert.Equal(t, [][]string{{"requests", "1000"}, {"nested inner", ""}}, limits.Rows)
+}
+
+func TestOutput(t *testing.T) {
+	found := []tables.Table{{
+		Name:    "People",
+		Headers: []string{"name", "note"},
+		Rows:    [][]string{{"Ann", "says \"hi\", twice"}},
+	}}
+
+	data, err := tables.ToJSON(found)
+	require.NoError(t, err)
+
+	var decoded []struct {
+		Name string              `json:"name"`
+		Rows []map[string]string `json:"rows"`
+	}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, "People", decoded[0].Name)
+	assert.Equal(t, "Ann", decoded[0].Rows[0]["name"])
+
+	csv, err := tables.ToCSVDocument(found)
+	require.NoError(t, err)
+	assert.Equal(t, "Table: People\nname,note\nAnn,\"says \"\"hi\"\", twice\"\n", csv)
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/ncecere/reader-go/internal/api/handlers"
 	"github.com/ncecere/reader-go/internal/api/middleware"
 	"github.com/ncecere/reader-go/internal/core/ai"
+	"github.com/ncecere/reader-go/internal/core/redact"
 	"github.com/ncecere/reader-go/internal/core/service"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/valyala/fasthttp/fasthttpadaptor"
@@ -26,7 +27,7 @@ type Config struct {
 }
 
 // New creates a new server instance
-func New(config *Config, browserService *service.Service, aiService *ai.Service) *Server {
+func New(config *Config, browserService *service.Service, aiService *ai.Service, redactor *redact.Redactor) *Server {
 	app := fiber.New(fiber.Config{
 		DisableStartupMessage: true,
 	})
@@ -35,15 +36,28 @@ func New(config *Config, browserService *service.Service, aiService *ai.Service)
 	app.Use(recover.New())
 	app.Use(logger.New())
 	app.Use(middleware.NewMetricsMiddleware())
+	app.Use(middleware.AutoScroll())
+	app.Use(middleware.SetCookie())
 
 	// Create handlers
-	readerHandler := handlers.NewReaderHandler(browserService)
+	readerHandler := handlers.NewReaderHandler(browserService, redactor)
 	summaryHandler := handlers.NewSummaryHandler(browserService, aiService)
+	embedHandler := handlers.NewEmbedHandler(browserService, aiService)
+	askHandler := handlers.NewAskHandler(browserService, aiService)
+	extractHandler := handlers.NewExtractHandler(browserService, aiService)
+	translateHandler := handlers.NewTranslateHandler(browserService, aiService)
 
 	// Setup routes
 	app.Get("/metrics", MetricsHandler())
-	app.Get("/summary/*", summaryHandler.HandleRequest)
+	aiProvider := middleware.AIProvider(aiService)
+	aiUsage := middleware.AIUsage(aiService)
+	app.Get("/summary/*", aiUsage, aiProvider, summaryHandler.HandleRequest)
+	app.Get("/embed/*", aiUsage, aiProvider, embedHandler.HandleRequest)
+	app.Get("/translate/*", aiUsage, aiProvider, translateHandler.HandleRequest)
+	app.Post("/ask", aiUsage, aiProvider, askHandler.HandleRequest)
+	app.Post("/extract", aiUsage, aiProvider, extractHandler.HandleRequest)
 	app.Get("/*", readerHandler.HandleRequest)
+	app.Post("/*", readerHandler.HandleActions)
 
 	return &Server{
 		app:    app,